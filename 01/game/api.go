@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var httpAddr = flag.String("http", "", "адрес HTTP/JSON API, например :8080; каждая сессия получает свой мир")
+
+// sessionIdle - через сколько без команд сессия удаляется
+const sessionIdle = time.Hour
+
+// maxRequestBody - самое большое тело запроса, которое разбирает API
+const maxRequestBody = 64 << 10
+
+// maxSessions - сколько сессий может быть одновременно, у каждой свой мир в памяти
+const maxSessions = 1000
+
+// session - отдельная игра: свой мир и свой игрок, с другими сессиями ничего общего
+type session struct {
+	sync.Mutex
+	player *world.Player
+	// сообщения игроку за время последней команды
+	notes    []string
+	lastUsed time.Time
+}
+
+// api - HTTP/JSON интерфейс к игре:
+//
+//	POST   /sessions               {"lang": "en", "format": "json"} - новая игра
+//	GET    /sessions/{id}                                           - что видит игрок
+//	POST   /sessions/{id}/commands {"command": "идти коридор"}      - выполнить команду
+//	DELETE /sessions/{id}                                           - закончить игру
+type api struct {
+	mu          sync.Mutex
+	sessions    map[string]*session
+	maxSessions int
+	newWorld    func() (*world.World, error)
+	now         func() time.Time
+}
+
+type sessionRequest struct {
+	Lang string `json:"lang"`
+	// формат ответа на осмотреться: text, ansi или json
+	Format string `json:"format"`
+}
+
+type sessionResponse struct {
+	ID   string     `json:"id"`
+	View world.View `json:"view"`
+}
+
+type commandRequest struct {
+	Command string `json:"command"`
+}
+
+type commandResponse struct {
+	Answer string     `json:"answer"`
+	Notes  []string   `json:"notes"`
+	View   world.View `json:"view"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newAPI(newWorld func() (*world.World, error)) *api {
+	return &api{
+		sessions:    make(map[string]*session),
+		maxSessions: maxSessions,
+		newWorld:    newWorld,
+		now:         time.Now,
+	}
+}
+
+func serveHTTP(addr string) error {
+	// мир проверяется сразу, а не при первой сессии
+	if _, err := loadWorld(); err != nil {
+		return err
+	}
+	log.Printf("http api started at %s", addr)
+	return http.ListenAndServe(addr, newAPI(loadWorld).handler())
+}
+
+func (api *api) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /sessions", api.createSession)
+	mux.HandleFunc("GET /sessions/{id}", api.withSession(api.showSession))
+	mux.HandleFunc("POST /sessions/{id}/commands", api.withSession(api.runSessionCommand))
+	mux.HandleFunc("DELETE /sessions/{id}", api.deleteSession)
+	return mux
+}
+
+func (api *api) createSession(w http.ResponseWriter, r *http.Request) {
+	var request sessionRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(w, r, &request); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"bad request: " + err.Error()})
+			return
+		}
+	}
+	if request.Lang != "" && !isLocale(request.Lang) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"unknown lang " + request.Lang})
+		return
+	}
+	if _, ok := world.Renderers[request.Format]; request.Format != "" && !ok {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"unknown format " + request.Format})
+		return
+	}
+	gameWorld, err := api.newWorld()
+	if err != nil {
+		log.Printf("cant create world: %s", err)
+		writeJSON(w, http.StatusInternalServerError, errorResponse{"cant create world"})
+		return
+	}
+	id := newSessionID()
+	// сохранения сессии лежат отдельно, чужие слоты ей не видны
+	gameWorld.SaveScope = id
+	current := &session{player: gameWorld.NewPlayer(), lastUsed: api.now()}
+	if request.Lang != "" {
+		current.player.SetLocale(request.Lang)
+	}
+	if request.Format != "" {
+		current.player.SetRenderer(request.Format)
+	}
+	gameWorld.Events.Subscribe(current.player, func(text string) {
+		current.notes = append(current.notes, text)
+	})
+
+	api.mu.Lock()
+	api.expire()
+	full := len(api.sessions) >= api.maxSessions
+	if !full {
+		api.sessions[id] = current
+	}
+	api.mu.Unlock()
+	if full {
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{"too many sessions"})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sessionResponse{ID: id, View: current.player.View()})
+}
+
+// withSession находит сессию по id из пути и держит её блокировку, пока идёт запрос
+func (api *api) withSession(handle func(w http.ResponseWriter, r *http.Request, current *session)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		api.expire()
+		current, ok := api.sessions[r.PathValue("id")]
+		api.mu.Unlock()
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{"unknown session"})
+			return
+		}
+		current.Lock()
+		defer current.Unlock()
+		current.lastUsed = api.now()
+		handle(w, r, current)
+	}
+}
+
+func (api *api) showSession(w http.ResponseWriter, r *http.Request, current *session) {
+	current.player.World.Lock()
+	defer current.player.World.Unlock()
+	writeJSON(w, http.StatusOK, current.player.View())
+}
+
+func (api *api) runSessionCommand(w http.ResponseWriter, r *http.Request, current *session) {
+	var request commandRequest
+	if err := decodeJSON(w, r, &request); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"bad request: " + err.Error()})
+		return
+	}
+	if request.Command == "" {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"empty command"})
+		return
+	}
+	current.notes = []string{}
+	answer := runCommand(current.player, request.Command)
+
+	current.player.World.Lock()
+	defer current.player.World.Unlock()
+	writeJSON(w, http.StatusOK, commandResponse{Answer: answer, Notes: current.notes, View: current.player.View()})
+}
+
+func (api *api) deleteSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	api.mu.Lock()
+	api.expire()
+	_, ok := api.sessions[id]
+	if ok {
+		api.remove(id)
+	}
+	api.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{"unknown session"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// expire удаляет заброшенные сессии. Вызывается под api.mu на каждом запросе,
+// так что сессии не копятся, даже если новые не создаются
+func (api *api) expire() {
+	for id, current := range api.sessions {
+		if current.TryLock() {
+			idle := api.now().Sub(current.lastUsed)
+			current.Unlock()
+			if idle > sessionIdle {
+				api.remove(id)
+			}
+		}
+	}
+}
+
+// remove удаляет сессию вместе с её сохранениями, вызывается под api.mu
+func (api *api) remove(id string) {
+	delete(api.sessions, id)
+	if err := os.RemoveAll(filepath.Join(*savesDir, id)); err != nil {
+		log.Printf("cant remove saves of session %s: %s", id, err)
+	}
+}
+
+func isLocale(name string) bool {
+	for _, locale := range world.Locales {
+		if string(locale) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newSessionID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(id)
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, body any) error {
+	return json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("cant write response: %s", err)
+	}
+}
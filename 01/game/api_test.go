@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+)
+
+func apiRequest(t *testing.T, server *httptest.Server, method, path string, body any, result any) int {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		data, _ = json.Marshal(body)
+	}
+	request, err := http.NewRequest(method, server.URL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if result != nil {
+		if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+	}
+	return response.StatusCode
+}
+
+func TestAPI(t *testing.T) {
+	*savesDir = t.TempDir()
+	server := httptest.NewServer(newAPI(loadWorld).handler())
+	defer server.Close()
+
+	var first, second sessionResponse
+	if status := apiRequest(t, server, "POST", "/sessions", nil, &first); status != http.StatusCreated {
+		t.Fatalf("create session: status %d", status)
+	}
+	apiRequest(t, server, "POST", "/sessions", sessionRequest{Lang: "en"}, &second)
+	if first.View.Room != "кухня" || second.View.Room != "kitchen" {
+		t.Errorf("start rooms: %q and %q", first.View.Room, second.View.Room)
+	}
+	if len(first.View.Tasks) != 2 || first.View.Exits[0].Name != "коридор" {
+		t.Errorf("bad start view: %+v", first.View)
+	}
+
+	var result commandResponse
+	for _, command := range []string{"идти коридор", "идти комната", "надеть рюкзак"} {
+		apiRequest(t, server, "POST", "/sessions/"+first.ID+"/commands", commandRequest{command}, &result)
+	}
+	if result.Answer != "вы надели: рюкзак" || !slices.Equal(result.View.Worn, []string{"рюкзак"}) {
+		t.Errorf("bad command result: %+v", result)
+	}
+	for _, command := range []string{"взять ключи", "взять конспекты", "идти коридор", "применить ключи дверь", "идти улица"} {
+		apiRequest(t, server, "POST", "/sessions/"+first.ID+"/commands", commandRequest{command}, &result)
+	}
+	if len(result.Notes) != 1 || result.View.Room != "улица" {
+		t.Errorf("bad quest progress: %+v", result)
+	}
+
+	var third sessionResponse
+	apiRequest(t, server, "POST", "/sessions", sessionRequest{Format: "json"}, &third)
+	apiRequest(t, server, "POST", "/sessions/"+third.ID+"/commands", commandRequest{"осмотреться"}, &result)
+	var look world.Look
+	if err := json.Unmarshal([]byte(result.Answer), &look); err != nil || look.Room != "кухня" {
+		t.Errorf("json look %q: %v", result.Answer, err)
+	}
+
+	// у второй сессии свой мир, в нём ничего не сдвинулось
+	var view world.View
+	apiRequest(t, server, "GET", "/sessions/"+second.ID, nil, &view)
+	if view.Room != "kitchen" || len(view.Worn) != 0 {
+		t.Errorf("sessions share a world: %+v", view)
+	}
+	// и свои сохранения
+	apiRequest(t, server, "POST", "/sessions/"+first.ID+"/commands", commandRequest{"сохранить слот"}, &result)
+	if result.Answer != "игра сохранена: слот" {
+		t.Errorf("save: %s", result.Answer)
+	}
+	apiRequest(t, server, "POST", "/sessions/"+second.ID+"/commands", commandRequest{"load слот"}, &result)
+	if result.Answer != "failed to load the game" {
+		t.Errorf("another session's slot loaded: %s", result.Answer)
+	}
+	apiRequest(t, server, "POST", "/sessions/"+first.ID+"/commands", commandRequest{"загрузить слот"}, &result)
+	if result.Answer != "игра загружена: слот" {
+		t.Errorf("own slot: %s", result.Answer)
+	}
+
+	var failure errorResponse
+	if status := apiRequest(t, server, "GET", "/sessions/nope", nil, &failure); status != http.StatusNotFound {
+		t.Errorf("unknown session: status %d", status)
+	}
+	if status := apiRequest(t, server, "POST", "/sessions", sessionRequest{Lang: "fr"}, &failure); status != http.StatusBadRequest {
+		t.Errorf("unknown lang: status %d", status)
+	}
+	huge := commandRequest{strings.Repeat("а", maxRequestBody)}
+	if status := apiRequest(t, server, "POST", "/sessions/"+first.ID+"/commands", huge, &failure); status != http.StatusBadRequest {
+		t.Errorf("huge body: status %d", status)
+	}
+	if status := apiRequest(t, server, "DELETE", "/sessions/"+first.ID, nil, nil); status != http.StatusNoContent {
+		t.Errorf("delete session: status %d", status)
+	}
+	if _, err := os.Stat(filepath.Join(*savesDir, first.ID)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("saves of deleted session left: %v", err)
+	}
+	if status := apiRequest(t, server, "GET", "/sessions/"+first.ID, nil, &failure); status != http.StatusNotFound {
+		t.Errorf("deleted session: status %d", status)
+	}
+}
+
+func TestAPIExpire(t *testing.T) {
+	*savesDir = t.TempDir()
+	api := newAPI(loadWorld)
+	now := time.Now()
+	api.now = func() time.Time { return now }
+	server := httptest.NewServer(api.handler())
+	defer server.Close()
+
+	var old, fresh sessionResponse
+	var result commandResponse
+	apiRequest(t, server, "POST", "/sessions", nil, &old)
+	apiRequest(t, server, "POST", "/sessions/"+old.ID+"/commands", commandRequest{"сохранить слот"}, &result)
+	if result.Answer != "игра сохранена: слот" {
+		t.Fatalf("save: %s", result.Answer)
+	}
+	now = now.Add(sessionIdle / 2)
+	apiRequest(t, server, "POST", "/sessions", nil, &fresh)
+	now = now.Add(sessionIdle/2 + time.Minute)
+	// заброшенные сессии удаляются на любом запросе, не только при создании новой
+	apiRequest(t, server, "GET", "/sessions/"+fresh.ID, nil, nil)
+	if _, ok := api.sessions[old.ID]; ok || len(api.sessions) != 1 {
+		t.Errorf("idle session not expired, %d sessions", len(api.sessions))
+	}
+	if _, err := os.Stat(filepath.Join(*savesDir, old.ID)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("saves of expired session left: %v", err)
+	}
+}
+
+func TestAPILimits(t *testing.T) {
+	*savesDir = t.TempDir()
+	api := newAPI(loadWorld)
+	api.maxSessions = 2
+	server := httptest.NewServer(api.handler())
+	defer server.Close()
+
+	var current sessionResponse
+	apiRequest(t, server, "POST", "/sessions", nil, &current)
+	apiRequest(t, server, "POST", "/sessions", nil, nil)
+	var failure errorResponse
+	if status := apiRequest(t, server, "POST", "/sessions", nil, &failure); status != http.StatusServiceUnavailable {
+		t.Errorf("too many sessions: status %d", status)
+	}
+
+	var result commandResponse
+	for i := 0; i < maxScopedSlots; i++ {
+		apiRequest(t, server, "POST", "/sessions/"+current.ID+"/commands", commandRequest{"сохранить слот" + strconv.Itoa(i)}, &result)
+	}
+	if result.Answer != "игра сохранена: слот"+strconv.Itoa(maxScopedSlots-1) {
+		t.Errorf("save: %s", result.Answer)
+	}
+	apiRequest(t, server, "POST", "/sessions/"+current.ID+"/commands", commandRequest{"сохранить лишний"}, &result)
+	if result.Answer != "слишком много сохранений, перезапишите одно из старых" {
+		t.Errorf("too many slots: %s", result.Answer)
+	}
+	apiRequest(t, server, "POST", "/sessions/"+current.ID+"/commands", commandRequest{"сохранить слот0"}, &result)
+	if result.Answer != "игра сохранена: слот0" {
+		t.Errorf("overwrite slot: %s", result.Answer)
+	}
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"strings"
+)
+
+// commands - команды, доступные в любой комнате.
+// Заполняется в init, потому что помощь сама читает список команд
+var commands *world.Commands
+
+func init() {
+	commands = &world.Commands{}
+	for _, command := range []*world.Command{
+		{
+			Name:    "осмотреться",
+			Aliases: []string{"оглядеться", "осмотрись", "оглядись", "смотреть"},
+			Names:   map[world.Locale]string{world.English: "look"},
+			Help:    "описание комнаты",
+			Handler: func(player *world.Player, args []string) string {
+				return player.LookAround()
+			},
+		},
+		{
+			Name:     "осмотреть",
+			Aliases:  []string{"осмотри", "рассмотреть", "рассмотри", "изучить", "изучи"},
+			Names:    map[world.Locale]string{world.English: "examine"},
+			Optional: []string{"цель", "хранилище"},
+			Help:     "подробно осмотреть комнату, предмет, хранилище или дверь, можно уточнить, где лежит предмет",
+			Handler: func(player *world.Player, args []string) string {
+				name, place := "", ""
+				if len(args) > 0 {
+					name = args[0]
+				}
+				if len(args) > 1 {
+					place = args[1]
+				}
+				return player.Examine(name, place)
+			},
+		},
+		{
+			Name:    "идти",
+			Aliases: []string{"иди", "пойти", "пойди", "ступай"},
+			Names:   map[world.Locale]string{world.English: "go"},
+			Args:    []string{"комната"},
+			Help:    "пройти в соседнюю комнату",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Go(args[0])
+			},
+		},
+		{
+			Name:     "взять",
+			Aliases:  []string{"возьми", "брать", "бери", "забрать", "забери"},
+			Names:    map[world.Locale]string{world.English: "take"},
+			Args:     []string{"предмет"},
+			Optional: []string{"хранилище"},
+			Help:     "положить предмет в инвентарь, можно достать из хранилища",
+			Handler: func(player *world.Player, args []string) string {
+				if len(args) > 1 {
+					return player.TakeItemFrom(world.Item(args[0]), args[1])
+				}
+				return player.TakeItem(world.Item(args[0]))
+			},
+		},
+		{
+			Name:    "положить",
+			Aliases: []string{"положи", "класть", "клади", "убрать", "убери"},
+			Names:   map[world.Locale]string{world.English: "put"},
+			Args:    []string{"предмет", "хранилище"},
+			Help:    "переложить предмет из инвентаря в хранилище",
+			Handler: func(player *world.Player, args []string) string {
+				return player.PutItem(world.Item(args[0]), args[1])
+			},
+		},
+		{
+			Name:    "выбросить",
+			Aliases: []string{"выброси", "бросить", "брось", "выкинуть", "выкинь"},
+			Names:   map[world.Locale]string{world.English: "drop"},
+			Args:    []string{"предмет"},
+			Help:    "выбросить предмет из инвентаря на пол",
+			Handler: func(player *world.Player, args []string) string {
+				return player.DropItem(world.Item(args[0]))
+			},
+		},
+		{
+			Name:    "инвентарь",
+			Aliases: []string{"и"},
+			Names:   map[world.Locale]string{world.English: "inventory"},
+			Help:    "что у вас с собой",
+			Handler: func(player *world.Player, args []string) string {
+				return player.ShowInventory()
+			},
+		},
+		{
+			Name:    "задания",
+			Aliases: []string{"квесты"},
+			Names:   map[world.Locale]string{world.English: "quests"},
+			Help:    "список заданий",
+			Handler: func(player *world.Player, args []string) string {
+				return player.QuestsList()
+			},
+		},
+		{
+			Name:    "открыть",
+			Aliases: []string{"открой", "отпереть", "отопри"},
+			Names:   map[world.Locale]string{world.English: "open"},
+			Args:    []string{"хранилище"},
+			Help:    "открыть шкаф, сейф, контейнер или дверь",
+			Handler: func(player *world.Player, args []string) string {
+				return player.OpenStorage(args[0])
+			},
+		},
+		{
+			Name:    "закрыть",
+			Aliases: []string{"закрой"},
+			Names:   map[world.Locale]string{world.English: "close"},
+			Args:    []string{"хранилище"},
+			Help:    "закрыть шкаф, сейф, контейнер или дверь",
+			Handler: func(player *world.Player, args []string) string {
+				return player.CloseStorage(args[0])
+			},
+		},
+		{
+			Name:    "надеть",
+			Aliases: []string{"надень", "одеть", "одень"},
+			Names:   map[world.Locale]string{world.English: "wear"},
+			Args:    []string{"предмет"},
+			Help:    "надеть предмет",
+			Handler: func(player *world.Player, args []string) string {
+				return player.WearItem(world.Item(args[0]))
+			},
+		},
+		{
+			Name:    "снять",
+			Aliases: []string{"сними", "снимать"},
+			Names:   map[world.Locale]string{world.English: "remove"},
+			Args:    []string{"предмет"},
+			Help:    "снять надетый предмет",
+			Handler: func(player *world.Player, args []string) string {
+				return player.TakeOff(world.Item(args[0]))
+			},
+		},
+		{
+			Name:    "экипировка",
+			Aliases: []string{"надето", "снаряжение"},
+			Names:   map[world.Locale]string{world.English: "equipment"},
+			Help:    "что на вас надето",
+			Handler: func(player *world.Player, args []string) string {
+				return player.ShowEquipment()
+			},
+		},
+		{
+			Name:    "применить",
+			Aliases: []string{"примени", "использовать", "используй"},
+			Names:   map[world.Locale]string{world.English: "use"},
+			Args:    []string{"предмет", "цель"},
+			Help:    "применить предмет из инвентаря",
+			Handler: func(player *world.Player, args []string) string {
+				return player.UseItem(world.Item(args[0]), args[1])
+			},
+		},
+		{
+			Name:  "карта",
+			Names: map[world.Locale]string{world.English: "map"},
+			Help:  "карта комнат, где вы побывали",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Map()
+			},
+		},
+		{
+			Name:     "атаковать",
+			Aliases:  []string{"атакуй", "ударить", "ударь", "бить", "бей"},
+			Names:    map[world.Locale]string{world.English: "attack"},
+			Args:     []string{"цель"},
+			Optional: []string{"предмет"},
+			Help:     "напасть на персонажа, можно ударить предметом из инвентаря",
+			Handler: func(player *world.Player, args []string) string {
+				var weapon world.Item
+				if len(args) > 1 {
+					weapon = world.Item(args[1])
+				}
+				return player.Attack(args[0], weapon)
+			},
+		},
+		{
+			Name:    "съесть",
+			Aliases: []string{"съешь", "выпить", "выпей", "есть", "пить"},
+			Names:   map[world.Locale]string{world.English: "eat"},
+			Args:    []string{"предмет"},
+			Help:    "съесть или выпить предмет из инвентаря, чтобы восстановить здоровье",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Eat(world.Item(args[0]))
+			},
+		},
+		{
+			Name:    "здоровье",
+			Aliases: []string{"состояние"},
+			Names:   map[world.Locale]string{world.English: "health"},
+			Help:    "здоровье и характеристики",
+			Handler: func(player *world.Player, args []string) string {
+				return player.ShowStats()
+			},
+		},
+		{
+			Name:    "сказать",
+			Aliases: []string{"скажи", "говорить", "говори"},
+			Names:   map[world.Locale]string{world.English: "say"},
+			Rest:    true,
+			Help:    "сказать что-то игрокам в комнате",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Say(strings.Join(args, " "))
+			},
+		},
+		{
+			Name:    "поговорить",
+			Aliases: []string{"поговори", "заговорить"},
+			Names:   map[world.Locale]string{world.English: "talk"},
+			Args:    []string{"собеседник"},
+			Help:    "начать разговор с персонажем",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Talk(args[0])
+			},
+		},
+		{
+			Name:    "ответить",
+			Aliases: []string{"ответь"},
+			Names:   map[world.Locale]string{world.English: "answer"},
+			Args:    []string{"номер"},
+			Help:    "выбрать вариант ответа в разговоре, можно просто написать номер",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Answer(args[0])
+			},
+		},
+		{
+			Name:    "время",
+			Aliases: []string{"часы"},
+			Names:   map[world.Locale]string{world.English: "time"},
+			Instant: true,
+			Help:    "который час",
+			Handler: func(player *world.Player, args []string) string {
+				return player.ShowTime()
+			},
+		},
+		{
+			Name:    "отменить",
+			Aliases: []string{"отмени", "назад"},
+			Names:   map[world.Locale]string{world.English: "undo"},
+			Instant: true,
+			Help:    "отменить последнее действие",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Undo()
+			},
+		},
+		{
+			Name:    "повторить",
+			Aliases: []string{"повтори"},
+			Names:   map[world.Locale]string{world.English: "redo"},
+			Instant: true,
+			Help:    "повторить отменённое действие",
+			Handler: func(player *world.Player, args []string) string {
+				return player.Redo()
+			},
+		},
+		{
+			Name:    "журнал",
+			Aliases: []string{"история"},
+			Names:   map[world.Locale]string{world.English: "log"},
+			Instant: true,
+			Help:    "что вы успели сделать",
+			Handler: func(player *world.Player, args []string) string {
+				return player.HistoryList()
+			},
+		},
+		{
+			Name:    "сохранить",
+			Aliases: []string{"сохрани"},
+			Names:   map[world.Locale]string{world.English: "save"},
+			Args:    []string{"слот"},
+			Instant: true,
+			Help:    "сохранить игру",
+			Handler: func(player *world.Player, args []string) string {
+				return saveGame(player, args[0])
+			},
+		},
+		{
+			Name:    "загрузить",
+			Aliases: []string{"загрузи"},
+			Names:   map[world.Locale]string{world.English: "load"},
+			Args:    []string{"слот"},
+			Instant: true,
+			Help:    "загрузить сохранённую игру",
+			Handler: func(player *world.Player, args []string) string {
+				return loadGame(player, args[0])
+			},
+		},
+		{
+			Name:    "язык",
+			Aliases: []string{"локаль"},
+			Names:   map[world.Locale]string{world.English: "language"},
+			Args:    []string{"язык"},
+			Instant: true,
+			Help:    "сменить язык игры: ru или en",
+			Handler: func(player *world.Player, args []string) string {
+				return player.SetLocale(args[0])
+			},
+		},
+		{
+			Name:    "формат",
+			Aliases: []string{"вывод"},
+			Names:   map[world.Locale]string{world.English: "format"},
+			Args:    []string{"формат"},
+			Instant: true,
+			Help:    "как показывать описание комнаты: text, ansi или json",
+			Handler: func(player *world.Player, args []string) string {
+				return player.SetRenderer(args[0])
+			},
+		},
+		{
+			Name:    "помощь",
+			Aliases: []string{"справка"},
+			Names:   map[world.Locale]string{world.English: "help"},
+			Instant: true,
+			Help:    "список команд",
+			Handler: help,
+		},
+	} {
+		if err := commands.Register(command); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func help(player *world.Player, args []string) string {
+	result := commands.HelpList(player)
+	if roomCommands := player.CurrentRoom.Commands.HelpList(player); roomCommands != "" {
+		result += "\n" + roomCommands
+	}
+	return result
+}
+
+// findCommand ищет сначала команды комнаты, чтобы комната могла переопределить общую команду
+func findCommand(player *world.Player, name string) *world.Command {
+	if command := player.CurrentRoom.Commands.Find(name); command != nil {
+		return command
+	}
+	return commands.Find(name)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"strings"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	initGame()
+	cases := []gameCase{
+		{1, "идти", "не хватает аргументов: идти <комната>"},
+		{2, "применить ключи", "не хватает аргументов: применить <предмет> <цель>"},
+		{3, "осмотреться кругом", "слишком много аргументов: осмотреться"},
+		{4, "идти луна", "нет пути в луна"},
+		{5, "", "неизвестная команда"},
+		{6, "оглядеться", "ты находишься на кухне, на столе: чай, надо собрать рюкзак и идти в универ. можно пройти - коридор"},
+	}
+	for _, item := range cases {
+		if answer := handleCommand(item.command); answer != item.answer {
+			t.Error("step:", item.step,
+				"\n\tcmd:", item.command,
+				"\n\tresult:  ", answer,
+				"\n\texpected:", item.answer)
+		}
+	}
+}
+
+func TestRoomCommands(t *testing.T) {
+	initGame()
+	err := gameWorld.Rooms["кухня"].Commands.Register(&world.Command{
+		Name: "завтракать",
+		Help: "поесть перед универом",
+		Handler: func(player *world.Player, args []string) string {
+			return "некогда завтракать"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer := handleCommand("завтракать"); answer != "некогда завтракать" {
+		t.Errorf("unexpected answer: %s", answer)
+	}
+	if answer := handleCommand("помощь"); !strings.Contains(answer, "завтракать - поесть перед универом") {
+		t.Errorf("room command is missing in help: %s", answer)
+	}
+	handleCommand("идти коридор")
+	if answer := handleCommand("завтракать"); answer != "неизвестная команда" {
+		t.Errorf("room command works outside room: %s", answer)
+	}
+	if answer := handleCommand("помощь"); strings.Contains(answer, "завтракать") {
+		t.Errorf("help lists command of another room: %s", answer)
+	}
+}
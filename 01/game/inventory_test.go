@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestInventory(t *testing.T) {
+	initGame()
+	cases := []gameCase{
+		{1, "инвентарь", "инвентарь пуст"},
+		{2, "взять чай", "некуда класть"},
+		{3, "идти коридор", "ничего интересного. можно пройти - кухня, комната, улица"},
+		{4, "идти комната", "ты в своей комнате. можно пройти - коридор"},
+		{5, "надеть рюкзак", "вы надели: рюкзак"},
+		{6, "взять ключи", "предмет добавлен в инвентарь: ключи"},
+		{7, "взять конспекты", "предмет добавлен в инвентарь: конспекты"},
+		{8, "инвентарь", "в инвентаре: ключи, конспекты. предметов 2/5, вес 3/10, объём 4/8"},
+		{9, "выбросить чай", "нет предмета в инвентаре - чай"},
+		{10, "выбросить конспекты", "вы выбросили: конспекты"},
+		{11, "осмотреться", "на полу: конспекты. можно пройти - коридор"},
+		{12, "и", "в инвентаре: ключи. предметов 1/5, вес 1/10, объём 1/8"},
+	}
+	for _, item := range cases {
+		if answer := handleCommand(item.command); answer != item.answer {
+			t.Error("step:", item.step,
+				"\n\tcmd:", item.command,
+				"\n\tresult:  ", answer,
+				"\n\texpected:", item.answer)
+		}
+	}
+}
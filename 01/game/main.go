@@ -2,121 +2,157 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	_ "embed"
+	"flag"
 	"fmt"
 	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"io"
+	"log"
 	"os"
-	"slices"
 	"strings"
 )
 
+// Мир по умолчанию: кухня, коридор, комната и улица
+//
+//go:embed worlds/default.json
+var defaultWorld []byte
+
+var worldPath = flag.String("world", "", "файл с описанием мира, по умолчанию встроенный")
+var lang = flag.String("lang", "ru", "язык игры: ru или en")
+var renderFormat = flag.String("format", "text", "как показывать описание комнаты: text, ansi или json")
+
+// генератор подземелий печатает файл мира, который потом можно загрузить через -world
+var generateRooms = flag.Int("generate", 0, "напечатать случайное подземелье из стольких комнат и выйти")
+var generateSeed = flag.Int64("seed", 1, "seed для -generate")
+
+var printDOT = flag.Bool("dot", false, "напечатать граф мира в формате Graphviz и выйти")
+var validate = flag.Bool("validate", false, "проверить мир на недостижимые комнаты и невыполнимые задания и выйти")
+var solve = flag.Bool("solve", false, "напечатать кратчайшее прохождение всех заданий и выйти")
+
 // Глобальные переменные с экземплярами Игрока и мира
-var player world.Player
-var gameWorld map[string]*world.Room
+var player *world.Player
+var gameWorld *world.World
+
+// notes - сообщения игроку, например о выполненных заданиях, они печатаются после ответа на команду
+var notes []string
 
 func main() {
+	flag.Parse()
+	if *generateRooms > 0 {
+		options := world.GenerateOptions{
+			Seed:        *generateSeed,
+			Rooms:       *generateRooms,
+			LockedDoors: *generateRooms / 3,
+			Items:       *generateRooms / 2,
+		}
+		if err := world.GenerateFile(options, os.Stdout); err != nil {
+			log.Fatalf("cant generate dungeon: %s", err)
+		}
+		return
+	}
+	if *httpAddr != "" {
+		log.Fatal(serveHTTP(*httpAddr))
+	}
 	initGame()
+	if *printDOT {
+		fmt.Print(gameWorld.DOT())
+		return
+	}
+	if *validate {
+		problems := gameWorld.Validate()
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+	if *solve {
+		commands, err := gameWorld.Solve()
+		if err != nil {
+			log.Fatalf("cant solve world: %s", err)
+		}
+		fmt.Println(strings.Join(commands, "\n"))
+		return
+	}
+	player.SetLocale(*lang)
+	player.SetRenderer(*renderFormat)
+	if *listenAddr != "" {
+		log.Fatal(serve(*listenAddr, gameWorld))
+	}
+	var record io.Writer = io.Discard
+	if *recordPath != "" {
+		file, err := os.Create(*recordPath)
+		if err != nil {
+			log.Fatalf("cant create transcript: %s", err)
+		}
+		defer file.Close()
+		record = file
+	}
 	in := bufio.NewScanner(os.Stdin)
 	for in.Scan() {
 		command := in.Text()
-		fmt.Println(handleCommand(command))
+		answer := playCommand(command)
+		fmt.Println(answer)
+		if err := writeTranscriptStep(record, command, answer); err != nil {
+			log.Fatalf("cant write transcript: %s", err)
+		}
 	}
 }
 
-func initGame() {
-	kitchen := world.Room{
-		Name:      "кухня",
-		NextRooms: make([]*world.Room, 0, 1),
-		Storages: []*world.Storage{
-			{
-				NameInCase: "на столе",
-				Items:      []world.Item{"чай"},
-			},
-		},
-		Note:           "кухня, ничего интересного",
-		LookAroundNote: "ты находишься на кухне",
-		Tasks: []world.Task{
-			{Text: "собрать рюкзак", Done: func() bool {
-				inventory := player.Inventory
-				return slices.Contains(inventory, world.Item("ключи")) &&
-					slices.Contains(inventory, world.Item("конспекты"))
-			}},
-			{Text: "идти в универ", Done: func() bool {
-				return false
-			}},
-		},
-	}
-	hall := world.Room{
-		Name:      "коридор",
-		NextRooms: make([]*world.Room, 0, 3),
-		Note:      "ничего интересного",
-	}
-	bedroom := world.Room{
-		Name:      "комната",
-		NextRooms: make([]*world.Room, 0, 1),
-		Storages: []*world.Storage{
-			{
-				NameInCase: "на столе",
-				Items:      []world.Item{"ключи", "конспекты"},
-			},
-			{
-				NameInCase: "на стуле",
-				Items:      []world.Item{"рюкзак"},
-			},
-		},
-		Note: "ты в своей комнате",
-	}
-	outside := world.Room{
-		Name:      "улица",
-		NextRooms: make([]*world.Room, 0, 1),
-		Note:      "на улице весна",
-	}
-	home := world.Room{
-		Name: "домой",
+func loadWorld() (*world.World, error) {
+	if *worldPath != "" {
+		return world.LoadFile(*worldPath)
 	}
+	return world.Load(bytes.NewReader(defaultWorld))
+}
 
-	hallToOutsideDoor := world.Door{
-		IsClosed: true,
-		States:   map[bool]string{true: "дверь закрыта", false: "дверь открыта"},
-		Rooms:    []*world.Room{&hall, &outside},
+func initGame() {
+	loaded, err := loadWorld()
+	if err != nil {
+		log.Fatalf("cant load world: %s", err)
 	}
+	gameWorld = loaded
+	player = loaded.NewPlayer()
+	notes = nil
+	gameWorld.Events.Subscribe(player, func(text string) {
+		notes = append(notes, text)
+	})
+}
 
-	kitchen.NextRooms = append(kitchen.NextRooms, &hall)
-	bedroom.NextRooms = append(bedroom.NextRooms, &hall)
-	hall.NextRooms = append(hall.NextRooms, &kitchen, &bedroom, &outside)
-	outside.NextRooms = []*world.Room{&home}
+func handleCommand(command string) string {
+	return runCommand(player, command)
+}
 
-	hall.DoorFromRoom = &hallToOutsideDoor
-	outside.DoorFromRoom = &hallToOutsideDoor
+// playCommand возвращает ответ на команду вместе с сообщениями, пришедшими за время её выполнения
+func playCommand(command string) string {
+	notes = notes[:0]
+	lines := append([]string{handleCommand(command)}, notes...)
+	notes = notes[:0]
+	return strings.Join(lines, "\n")
+}
 
-	gameWorld = map[string]*world.Room{
-		"кухня":   &kitchen,
-		"коридор": &hall,
-		"комната": &bedroom,
-		"улица":   &outside,
-	}
+// runCommand выполняет команду от имени игрока. Мир общий для всех игроков,
+// поэтому команда целиком выполняется под его блокировкой
+func runCommand(player *world.Player, command string) string {
+	player.World.Lock()
+	defer player.World.Unlock()
 
-	player = world.Player{
-		CurrentRoom: &kitchen,
-		Inventory:   make([]world.Item, 0, 5),
+	parsed, answer := parseCommand(player, command)
+	if answer != "" {
+		return answer
 	}
-}
-
-func handleCommand(command string) string {
-	commandSplit := strings.Split(command, " ")
-	var res string
-	switch commandSplit[0] {
-	case "осмотреться":
-		res = player.LookAround()
-	case "идти":
-		res = player.GoToRoom(gameWorld[commandSplit[1]])
-	case "взять":
-		res = player.TakeItem(world.Item(commandSplit[1]))
-	case "надеть":
-		res = player.WearItem(world.Item(commandSplit[1]))
-	case "применить":
-		res = player.UseItem(world.Item(commandSplit[1]), commandSplit[2])
-	default:
-		res = "неизвестная команда"
+	if parsed.command.Instant {
+		return parsed.command.Run(player, parsed.args)
 	}
-	return res
+	before := player.World.SaveState(player)
+	result := parsed.command.Run(player, parsed.args)
+	player.UpdateQuests()
+	player.Record(command, result, before)
+	// события по времени в действие не входят: отмена не должна их откатывать
+	player.World.Tick(player.World.Clock.PerCommand)
+	player.UpdateQuests()
+	return result
 }
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"strings"
+	"unicode/utf8"
+)
+
+// служебные слова, которые выбрасываются из аргументов: "иди в коридор", "положи ключи на стол",
+// "put keys into backpack"
+var prepositions = map[string]bool{
+	"в": true, "во": true, "на": true, "из": true, "к": true, "ко": true,
+	"с": true, "со": true, "у": true, "до": true, "по": true,
+	"to": true, "into": true, "in": true, "on": true, "from": true, "at": true, "with": true,
+	"the": true, "a": true, "an": true,
+}
+
+// по названию аргумента команды понятно, среди каких объектов искать его значение
+var argNames = map[string]func(player *world.Player) []string{
+	"комната":    (*world.Player).RoomNames,
+	"предмет":    (*world.Player).ItemNames,
+	"цель":       (*world.Player).TargetNames,
+	"хранилище":  (*world.Player).TargetNames,
+	"собеседник": (*world.Player).NPCNames,
+}
+
+// окончания, отбрасываемые при сравнении словоформ, длинные проверяются раньше
+var endings = []string{
+	"ами", "ями", "ого", "его", "ому", "ему",
+	"ов", "ев", "ей", "ам", "ям", "ах", "ях", "ой", "ом", "ем", "ую", "юю", "ая", "яя", "ое", "ее", "ые", "ие", "ый", "ий", "ых", "их",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+}
+
+const minStemLen = 3
+
+type parsedCommand struct {
+	command *world.Command
+	args    []string
+}
+
+// parseCommand приводит ввод к виду, который понимают команды: нижний регистр, без лишних
+// пробелов и предлогов, с названиями объектов в той форме, в какой они заданы в мире.
+// Свободный текст команд с Rest передаётся как есть. Если что-то распознать не удалось, возвращается ответ игроку
+func parseCommand(player *world.Player, input string) (parsed parsedCommand, answer string) {
+	raw := strings.Fields(input)
+	fields := make([]string, len(raw))
+	for i, field := range raw {
+		fields[i] = strings.Trim(strings.ToLower(field), ".,!?")
+	}
+	if len(fields) == 0 || fields[0] == "" {
+		return parsed, player.T("неизвестная команда")
+	}
+
+	// во время разговора достаточно написать номер ответа
+	if player.Dialogue != nil && isNumber(fields[0]) {
+		fields = append([]string{"ответить"}, fields...)
+	}
+	parsed.command = findCommand(player, fields[0])
+	if parsed.command == nil {
+		if guess := closest(fields[0], commandNames(player)); guess != "" {
+			return parsed, player.T("неизвестная команда") + ". " + player.T("возможно, вы имели в виду: %s", guess)
+		}
+		return parsed, player.T("неизвестная команда")
+	}
+	if parsed.command.Rest {
+		// свободный текст вроде реплики не трогаем: ни регистр, ни знаки препинания
+		parsed.args = raw[1:]
+		return parsed, ""
+	}
+
+	guessed := false
+	for _, arg := range fields[1:] {
+		if prepositions[arg] {
+			continue
+		}
+		if len(parsed.args) < len(parsed.command.Args)+len(parsed.command.Optional) {
+			var ok bool
+			if arg, ok = resolveArg(player, parsed.command, len(parsed.args), arg); !ok {
+				guessed = true
+			}
+		}
+		parsed.args = append(parsed.args, arg)
+	}
+	if guessed {
+		suggestion := []string{parsed.command.Name}
+		if name, ok := parsed.command.Names[player.Locale]; ok {
+			suggestion[0] = name
+		}
+		for _, arg := range parsed.args {
+			suggestion = append(suggestion, player.T(arg))
+		}
+		return parsed, player.T("возможно, вы имели в виду: %s", strings.Join(suggestion, " "))
+	}
+	return parsed, ""
+}
+
+// resolveArg ищет объект по точному названию, затем по основе слова, затем по опечатке.
+// ok == false означает, что название угадано по опечатке и его надо подтвердить
+func resolveArg(player *world.Player, command *world.Command, position int, arg string) (string, bool) {
+	argName := ""
+	if position < len(command.Args) {
+		argName = command.Args[position]
+	} else {
+		argName = command.Optional[position-len(command.Args)]
+	}
+	namesFunc, ok := argNames[argName]
+	if !ok {
+		return arg, true
+	}
+	// названия на языке игрока сводятся к исходным русским
+	canonical := player.Canonical(namesFunc(player))
+	if name, ok := canonical[arg]; ok {
+		return name, true
+	}
+	names := make([]string, 0, len(canonical))
+	for name := range canonical {
+		names = append(names, name)
+	}
+	if argStem := stem(arg); utf8.RuneCountInString(argStem) >= minStemLen {
+		for _, name := range names {
+			if stem(name) == argStem {
+				return canonical[name], true
+			}
+		}
+	}
+	if guess := closest(arg, names); guess != "" {
+		return canonical[guess], false
+	}
+	return arg, true
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return word != ""
+}
+
+func commandNames(player *world.Player) []string {
+	var names []string
+	for _, registry := range []*world.Commands{&player.CurrentRoom.Commands, commands} {
+		for _, command := range registry.List() {
+			names = append(names, command.Name)
+			names = append(names, command.Aliases...)
+			for _, name := range command.Names {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+func stem(word string) string {
+	word = strings.ReplaceAll(word, "ё", "е")
+	for _, ending := range endings {
+		if trimmed, ok := strings.CutSuffix(word, ending); ok && utf8.RuneCountInString(trimmed) >= minStemLen {
+			return trimmed
+		}
+	}
+	return word
+}
+
+// closest возвращает единственное название на небольшом расстоянии от слова
+func closest(word string, names []string) (result string) {
+	maxDistance := 2
+	switch length := utf8.RuneCountInString(word); {
+	case length <= 2:
+		return ""
+	case length <= 4:
+		maxDistance = 1
+	}
+	best := maxDistance + 1
+	for _, name := range names {
+		distance := levenshtein(word, name)
+		switch {
+		case distance < best:
+			best, result = distance, name
+		case distance == best && name != result:
+			// два одинаково похожих варианта - лучше ничего не предлагать
+			result = ""
+		}
+	}
+	if best > maxDistance {
+		return ""
+	}
+	return result
+}
+
+func levenshtein(a, b string) int {
+	first, second := []rune(a), []rune(b)
+	prev := make([]int, len(second)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(first); i++ {
+		cur := make([]int, len(second)+1)
+		cur[0] = i
+		for j := 1; j <= len(second); j++ {
+			cost := 1
+			if first[i-1] == second[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(second)]
+}
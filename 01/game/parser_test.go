@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestParser(t *testing.T) {
+	initGame()
+	cases := []gameCase{
+		{1, "  Осмотреться  ", "ты находишься на кухне, на столе: чай, надо собрать рюкзак и идти в универ. можно пройти - коридор"},
+		{2, "иди в коридор", "ничего интересного. можно пройти - кухня, комната, улица"},
+		{3, "пойди  в   комнату", "ты в своей комнате. можно пройти - коридор"},
+		{4, "Надень Рюкзак", "вы надели: рюкзак"},
+		{5, "возьми ключ", "предмет добавлен в инвентарь: ключи"},
+		{6, "взять конспект", "предмет добавлен в инвентарь: конспекты"},
+		{7, "идти в коридор.", "ничего интересного. можно пройти - кухня, комната, улица"},
+		{8, "примени ключами к двери", "дверь открыта"},
+		{9, "идти улцца", "возможно, вы имели в виду: идти улица"},
+		{10, "взть ключи", "неизвестная команда. возможно, вы имели в виду: взять"},
+		{11, "скажи я в коридоре", "вы сказали: я в коридоре"},
+		{12, "идти на улицу", "на улице весна. можно пройти - домой"},
+		{13, "Сказать Привет, Мир!", "вы сказали: Привет, Мир!"},
+	}
+	for _, item := range cases {
+		if answer := handleCommand(item.command); answer != item.answer {
+			t.Error("step:", item.step,
+				"\n\tcmd:", item.command,
+				"\n\tresult:  ", answer,
+				"\n\texpected:", item.answer)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	for _, words := range [][]string{
+		{"ключ", "ключи", "ключами", "ключей"},
+		{"комната", "комнату", "комнате"},
+		{"кухня", "кухню", "кухне"},
+	} {
+		for _, word := range words[1:] {
+			if stem(word) != stem(words[0]) {
+				t.Errorf("%s and %s have different stems: %s, %s", words[0], word, stem(words[0]), stem(word))
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var savesDir = flag.String("saves", "saves", "каталог для сохранений игры")
+
+// имя слота становится именем файла, поэтому разделители путей в нём запрещены
+var slotRe = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// maxScopedSlots - сколько слотов может занять одна сессия HTTP API
+const maxScopedSlots = 20
+
+var errTooManySlots = errors.New("too many save slots")
+
+func slotPath(gameWorld *world.World, slot string) (string, error) {
+	if !slotRe.MatchString(slot) {
+		return "", fmt.Errorf("bad slot name %q", slot)
+	}
+	return filepath.Join(*savesDir, gameWorld.SaveScope, slot+".json"), nil
+}
+
+func saveGame(player *world.Player, slot string) string {
+	if err := writeSave(player, slot); err != nil {
+		log.Printf("cant save slot %q: %s", slot, err)
+		if errors.Is(err, errTooManySlots) {
+			return player.T("слишком много сохранений, перезапишите одно из старых")
+		}
+		return player.T("не удалось сохранить игру")
+	}
+	return player.T("игра сохранена: %s", slot)
+}
+
+func loadGame(player *world.Player, slot string) string {
+	if player.World.Shared {
+		return player.T("в общем мире загружать игру нельзя")
+	}
+	if err := readSave(player, slot); err != nil {
+		log.Printf("cant load slot %q: %s", slot, err)
+		return player.T("не удалось загрузить игру")
+	}
+	return player.T("игра загружена: %s", slot)
+}
+
+func writeSave(player *world.Player, slot string) error {
+	path, err := slotPath(player.World, slot)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// у сессий API место под сохранения ограничено, перезаписывать старые слоты можно
+	if player.World.SaveScope != "" {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			slots, err := filepath.Glob(filepath.Join(dir, "*.json"))
+			if err != nil {
+				return err
+			}
+			if len(slots) >= maxScopedSlots {
+				return errTooManySlots
+			}
+		}
+	}
+	// пишем во временный файл, чтобы оборванная запись не испортила прошлое сохранение
+	tmp, err := os.CreateTemp(dir, slot+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err = world.WriteState(tmp, player.World.SaveState(player)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readSave(player *world.Player, slot string) error {
+	path, err := slotPath(player.World, slot)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	state, err := world.ReadState(file)
+	if err != nil {
+		return err
+	}
+	if err = player.World.RestoreState(player, state); err != nil {
+		return err
+	}
+	player.ForgetHistory()
+	return nil
+}
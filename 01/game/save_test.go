@@ -0,0 +1,73 @@
+package main
+
+import (
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	*savesDir = t.TempDir()
+	initGame()
+	for _, command := range []string{
+		"идти коридор", "идти комната", "надеть рюкзак", "взять ключи", "идти коридор", "применить ключи дверь",
+	} {
+		handleCommand(command)
+	}
+	saved := gameWorld.SaveState(player)
+	if answer := handleCommand("сохранить первый"); answer != "игра сохранена: первый" {
+		t.Fatalf("unexpected answer: %s", answer)
+	}
+
+	initGame()
+	if answer := handleCommand("загрузить первый"); answer != "игра загружена: первый" {
+		t.Fatalf("unexpected answer: %s", answer)
+	}
+	if loaded := gameWorld.SaveState(player); !reflect.DeepEqual(saved, loaded) {
+		t.Errorf("state differs after load\n\tsaved:  %+v\n\tloaded: %+v", saved, loaded)
+	}
+	cases := []gameCase{
+		{1, "осмотреться", "пустая комната. можно пройти - кухня, комната, улица"},
+		{2, "идти комната", "ты в своей комнате. можно пройти - коридор"},
+		{3, "осмотреться", "на столе: конспекты. можно пройти - коридор"},
+		{4, "взять конспекты", "предмет добавлен в инвентарь: конспекты"},
+		{5, "идти коридор", "ничего интересного. можно пройти - кухня, комната, улица"},
+		{6, "идти улица", "на улице весна. можно пройти - домой"},
+	}
+	for _, item := range cases {
+		if answer := handleCommand(item.command); answer != item.answer {
+			t.Error("step:", item.step,
+				"\n\tcmd:", item.command,
+				"\n\tresult:  ", answer,
+				"\n\texpected:", item.answer)
+		}
+	}
+}
+
+func TestLoadRejectsBadSave(t *testing.T) {
+	*savesDir = t.TempDir()
+	initGame()
+	state := gameWorld.SaveState(player)
+	state.Version = world.StateVersion + 1
+	file, err := os.Create(filepath.Join(*savesDir, "чужой.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = world.WriteState(file, state); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	handleCommand("идти коридор")
+	before := gameWorld.SaveState(player)
+	for _, slot := range []string{"чужой", "нет_такого", "../main"} {
+		if answer := handleCommand("загрузить " + slot); answer != "не удалось загрузить игру" {
+			t.Errorf("slot %s: unexpected answer: %s", slot, answer)
+		}
+	}
+	if after := gameWorld.SaveState(player); !reflect.DeepEqual(before, after) {
+		t.Errorf("failed load changed world state")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"gitlab.vk-golang.com/vk-golang/lectures/01_intro/99_hw/game/world"
+	"log"
+	"net"
+	"strings"
+	"time"
+)
+
+var listenAddr = flag.String("listen", "", "адрес MUD-сервера, например :4000; без него игра читает stdin")
+var tickEvery = flag.Duration("tick", 0, "в режиме сервера игровая минута проходит с этим периодом, а не после каждой команды")
+
+// serve принимает подключения по TCP (подойдёт обычный telnet),
+// каждое подключение получает своего игрока в общем мире
+func serve(addr string, gameWorld *world.World) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("mud server started at %s", listener.Addr())
+	if *tickEvery > 0 {
+		gameWorld.Lock()
+		gameWorld.Clock.PerCommand = 0
+		gameWorld.Unlock()
+		go runClock(gameWorld, time.NewTicker(*tickEvery).C)
+	}
+	return serveListener(listener, gameWorld)
+}
+
+// runClock двигает игровое время на минуту при каждом сигнале таймера
+func runClock(gameWorld *world.World, ticks <-chan time.Time) {
+	for range ticks {
+		gameWorld.Lock()
+		gameWorld.Tick(1)
+		gameWorld.Unlock()
+	}
+}
+
+func serveListener(listener net.Listener, gameWorld *world.World) error {
+	gameWorld.Lock()
+	gameWorld.Shared = true
+	gameWorld.Unlock()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go handleConn(conn, gameWorld)
+	}
+}
+
+func handleConn(conn net.Conn, gameWorld *world.World) {
+	defer conn.Close()
+	log.Printf("player connected from %s", conn.RemoteAddr())
+
+	in := bufio.NewScanner(conn)
+	fmt.Fprint(conn, "как тебя зовут?\r\n")
+	if !in.Scan() {
+		return
+	}
+	name := strings.TrimSpace(in.Text())
+	if name == "" {
+		name = "незнакомец"
+	}
+
+	// ответы на команды и события других игроков пишет в соединение одна горутина,
+	// чтобы строки не перемешивались
+	out := make(chan string, 32)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for text := range out {
+			fmt.Fprintf(conn, "%s\r\n", text)
+		}
+	}()
+
+	gameWorld.Lock()
+	player := gameWorld.NewPlayer()
+	player.Name = name
+	gameWorld.Events.Subscribe(player, func(text string) {
+		// медленный клиент пропускает события, но не задерживает остальных
+		select {
+		case out <- text:
+		default:
+		}
+	})
+	gameWorld.Unlock()
+
+	for in.Scan() {
+		// telnet присылает строки с \r\n
+		command := strings.TrimSpace(in.Text())
+		if command == "" {
+			continue
+		}
+		out <- runCommand(player, command)
+	}
+
+	gameWorld.Lock()
+	player.Leave()
+	gameWorld.Events.Unsubscribe(player)
+	gameWorld.Unlock()
+	close(out)
+	<-written
+	log.Printf("player %s from %s disconnected", name, conn.RemoteAddr())
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testClient struct {
+	t      *testing.T
+	name   string
+	conn   net.Conn
+	in     *bufio.Reader
+	events []string
+}
+
+func connect(t *testing.T, addr, name string) *testClient {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second)) //nolint:errcheck
+	client := &testClient{t: t, name: name, conn: conn, in: bufio.NewReader(conn)}
+	client.readLine() // приглашение представиться
+	fmt.Fprintf(conn, "%s\r\n", name)
+	return client
+}
+
+func (client *testClient) readLine() string {
+	line, err := client.in.ReadString('\n')
+	if err != nil {
+		client.t.Error(err)
+	}
+	return strings.TrimRight(line, "\r\n")
+}
+
+// send возвращает ответ на команду, события от других игроков откладываются в events
+func (client *testClient) send(command string, others ...string) string {
+	fmt.Fprintf(client.conn, "%s\r\n", command)
+	for {
+		line := client.readLine()
+		isEvent := false
+		for _, other := range others {
+			isEvent = isEvent || strings.HasPrefix(line, other+" ")
+		}
+		if !isEvent {
+			return line
+		}
+		client.events = append(client.events, line)
+	}
+}
+
+func (client *testClient) waitEvent(event string) {
+	for _, got := range client.events {
+		if got == event {
+			return
+		}
+	}
+	for {
+		line := client.readLine()
+		if line == event || line == "" {
+			return
+		}
+	}
+}
+
+func TestServerSharedWorld(t *testing.T) {
+	initGame()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go serveListener(listener, gameWorld) //nolint:errcheck
+
+	names := []string{"петя", "вася"}
+	clients := make([]*testClient, 0, len(names))
+	for _, name := range names {
+		client := connect(t, listener.Addr().String(), name)
+		defer client.conn.Close()
+		client.send("идти коридор", names...)
+		client.send("идти комната", names...)
+		clients = append(clients, client)
+	}
+
+	// оба игрока одновременно пытаются надеть единственный рюкзак
+	answers := make([]string, len(clients))
+	wg := &sync.WaitGroup{}
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *testClient) {
+			defer wg.Done()
+			answers[i] = client.send("надеть рюкзак", names...)
+		}(i, client)
+	}
+	wg.Wait()
+
+	winner := -1
+	for i, answer := range answers {
+		switch answer {
+		case "вы надели: рюкзак":
+			if winner != -1 {
+				t.Errorf("backpack was worn twice")
+			}
+			winner = i
+		case "нет такого":
+		default:
+			t.Errorf("unexpected answer: %s", answer)
+		}
+	}
+	if winner == -1 {
+		t.Fatal("nobody wore backpack")
+	}
+
+	winnerClient, loserClient := clients[winner], clients[1-winner]
+	loserClient.waitEvent(winnerClient.name + " надел рюкзак")
+	if answer := winnerClient.send("сказать привет", names...); answer != "вы сказали: привет" {
+		t.Errorf("unexpected answer: %s", answer)
+	}
+	loserClient.waitEvent(winnerClient.name + " говорит: привет")
+	if answer := loserClient.send("загрузить слот", names...); answer != "в общем мире загружать игру нельзя" {
+		t.Errorf("load in shared world: %s", answer)
+	}
+	winnerClient.send("идти коридор", names...)
+	loserClient.waitEvent(winnerClient.name + " ушёл в коридор")
+
+	// вещи ушедшего игрока остаются на полу
+	loserClient.send("идти коридор", names...)
+	winnerClient.conn.Close()
+	loserClient.waitEvent(winnerClient.name + " ушёл из игры")
+	if answer := loserClient.send("надеть рюкзак", names...); answer != "вы надели: рюкзак" {
+		t.Errorf("backpack of disconnected player: %s", answer)
+	}
+}
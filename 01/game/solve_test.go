@@ -0,0 +1,24 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSolveDefaultWorld проигрывает найденное прохождение через разбор команд,
+// как если бы его набирал игрок
+func TestSolveDefaultWorld(t *testing.T) {
+	initGame()
+	commands, err := gameWorld.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, command := range commands {
+		handleCommand(command)
+	}
+	for _, line := range strings.Split(handleCommand("задания"), "\n") {
+		if !strings.HasSuffix(line, ": выполнено") {
+			t.Errorf("quest not done after %q: %s", commands, line)
+		}
+	}
+}
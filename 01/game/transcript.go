@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Запись игры: команда в строке после "> ", затем строки ответа до следующей команды.
+// Пустые строки и строки с # пропускаются, так что запись можно дополнять руками.
+// Строки ответа, которые иначе прочитались бы как пропуск или команда, пишутся после "\"
+
+var recordPath = flag.String("record", "", "записать команды и ответы в файл, его можно проиграть как тест")
+
+const commandPrefix = "> "
+
+const escapePrefix = `\`
+
+type transcriptStep struct {
+	// номер строки с командой в файле
+	line    int
+	command string
+	answer  string
+}
+
+func readTranscript(r io.Reader) ([]transcriptStep, error) {
+	var steps []transcriptStep
+	var answer []string
+	finish := func() {
+		if len(steps) > 0 {
+			steps[len(steps)-1].answer = strings.Join(answer, "\n")
+		}
+		answer = nil
+	}
+	in := bufio.NewScanner(r)
+	for line := 1; in.Scan(); line++ {
+		text := in.Text()
+		switch {
+		case strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#"):
+		case strings.HasPrefix(text, commandPrefix):
+			finish()
+			steps = append(steps, transcriptStep{line: line, command: strings.TrimPrefix(text, commandPrefix)})
+		case len(steps) == 0:
+			return nil, fmt.Errorf("line %d: answer before first command", line)
+		default:
+			answer = append(answer, strings.TrimPrefix(text, escapePrefix))
+		}
+	}
+	finish()
+	return steps, in.Err()
+}
+
+func writeTranscriptStep(w io.Writer, command, answer string) error {
+	lines := strings.Split(answer, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, commandPrefix) || strings.HasPrefix(line, escapePrefix) {
+			lines[i] = escapePrefix + line
+		}
+	}
+	_, err := fmt.Fprintf(w, "%s%s\n%s\n\n", commandPrefix, command, strings.Join(lines, "\n"))
+	return err
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTranscripts проигрывает каждую запись из testdata/transcripts на новом мире.
+// Новую запись можно сделать самой игрой: go run . -record testdata/transcripts/имя.txt
+func TestTranscripts(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "transcripts", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no transcripts")
+	}
+	for _, path := range paths {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".txt"), func(t *testing.T) {
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			steps, err := readTranscript(file)
+			if err != nil {
+				t.Fatalf("%s: %s", path, err)
+			}
+			initGame()
+			for i, step := range steps {
+				if answer := playCommand(step.command); answer != step.answer {
+					t.Errorf("%s:%d step: %d\n\tcmd: %s\n\tresult:   %s\n\texpected: %s",
+						path, step.line, i+1, step.command, answer, step.answer)
+				}
+			}
+		})
+	}
+}
+
+func TestReadTranscript(t *testing.T) {
+	steps, err := readTranscript(strings.NewReader("# комментарий\n\n> помощь\nпервая\nвторая\n\n> время\nдень 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].line != 3 || steps[0].answer != "первая\nвторая" || steps[1].command != "время" {
+		t.Errorf("unexpected steps: %+v", steps)
+	}
+	if _, err = readTranscript(strings.NewReader("ответ без команды\n")); err == nil {
+		t.Error("expected error for answer before command")
+	}
+
+	// ответ с пустыми строками и строками, похожими на комментарий или команду, читается как записан
+	answers := []string{"первая\n\n# не комментарий\n> не команда\n\\косая", "", "  "}
+	var record strings.Builder
+	for _, answer := range answers {
+		if err := writeTranscriptStep(&record, "сказать", answer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	steps, err = readTranscript(strings.NewReader(record.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != len(answers) {
+		t.Fatalf("unexpected steps: %+v", steps)
+	}
+	for i, step := range steps {
+		if step.answer != answers[i] {
+			t.Errorf("[%d] answer %q read back as %q", i, answers[i], step.answer)
+		}
+	}
+}
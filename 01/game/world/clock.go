@@ -0,0 +1,135 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+)
+
+const minutesPerDay = 24 * 60
+
+var seasons = []string{"весна", "лето", "осень", "зима"}
+
+// Clock - игровое время. Minute - сколько минут прошло с начала игры
+type Clock struct {
+	Minute int
+	// время суток в начале игры, минуты с полуночи
+	Start int
+	// на сколько минут сдвигается время после каждой команды, 0 - время идёт само по таймеру
+	PerCommand    int
+	DaysPerSeason int
+	// сезон в первый день игры, индекс в seasons
+	StartSeason int
+}
+
+func (clock *Clock) timeOfDay(minute int) int {
+	return (clock.Start + minute) % minutesPerDay
+}
+
+func (clock *Clock) day(minute int) int {
+	return (clock.Start + minute) / minutesPerDay
+}
+
+func (clock *Clock) season(minute int) string {
+	if clock.DaysPerSeason <= 0 {
+		return seasons[clock.StartSeason]
+	}
+	return seasons[(clock.StartSeason+clock.day(minute)/clock.DaysPerSeason)%len(seasons)]
+}
+
+// ShowTime - текущее время на языке игрока
+func (player *Player) ShowTime() string {
+	clock := &player.World.Clock
+	timeOfDay := clock.timeOfDay(clock.Minute)
+	return player.T("день %d, %02d:%02d, %s", clock.day(clock.Minute)+1, timeOfDay/60, timeOfDay%60, Name(clock.season(clock.Minute)))
+}
+
+// parseTimeOfDay разбирает время вида 22:00
+func parseTimeOfDay(text string) (int, error) {
+	var hours, minutes int
+	if _, err := fmt.Sscanf(text, "%d:%d", &hours, &minutes); err != nil {
+		return 0, fmt.Errorf("bad time %q, expected hh:mm", text)
+	}
+	if hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("bad time %q", text)
+	}
+	return hours*60 + minutes, nil
+}
+
+// TimedEvent - событие комнаты по расписанию. Срабатывает однажды через After минут
+// после начала игры, каждый день в время At или с наступлением сезона Season
+type TimedEvent struct {
+	After  int    `json:"after,omitempty"`
+	At     string `json:"at,omitempty"`
+	Season string `json:"season,omitempty"`
+
+	Effect
+
+	at int
+}
+
+// due проверяет, наступило ли событие в промежутке (from, to]
+func (event *TimedEvent) due(clock *Clock, from, to int) bool {
+	switch {
+	case event.At != "":
+		// время суток at попадает в промежуток, если до него от from меньше, чем длина промежутка
+		wait := (event.at - clock.timeOfDay(from) + minutesPerDay) % minutesPerDay
+		if wait == 0 {
+			wait = minutesPerDay
+		}
+		return wait <= to-from
+	case event.Season != "":
+		return clock.season(from) != clock.season(to) && clock.season(to) == event.Season
+	default:
+		return from < event.After && event.After <= to
+	}
+}
+
+// Tick двигает время вперёд и запускает наступившие события
+func (world *World) Tick(minutes int) {
+	if minutes <= 0 {
+		return
+	}
+	from := world.Clock.Minute
+	world.Clock.Minute += minutes
+	// порядок комнат в map случаен, события запускаются в порядке названий комнат
+	var names []string
+	for name, room := range world.Rooms {
+		if len(room.Events) > 0 {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		room := world.Rooms[name]
+		for _, event := range room.Events {
+			if event.due(&world.Clock, from, world.Clock.Minute) {
+				event.apply(world, room)
+				// мир изменился не действием игрока, отменять действия до этого уже нельзя
+				world.revision++
+			}
+		}
+	}
+}
+
+func (world *World) checkTimedEvent(event *TimedEvent) (err error) {
+	triggers := 0
+	if event.At != "" {
+		triggers++
+		if event.at, err = parseTimeOfDay(event.At); err != nil {
+			return err
+		}
+	}
+	if event.Season != "" {
+		triggers++
+		if !slices.Contains(seasons, event.Season) {
+			return fmt.Errorf("unknown season %q", event.Season)
+		}
+	}
+	if event.After > 0 {
+		triggers++
+	}
+	if triggers != 1 {
+		return fmt.Errorf("exactly one of after, at and season must be set")
+	}
+	return world.checkEffect(event.Effect)
+}
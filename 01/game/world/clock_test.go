@@ -0,0 +1,79 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+const clockWorld = `{
+	"start": "кухня",
+	"time": {"start": "22:50", "per_command": 5, "days_per_season": 1, "season": "зима"},
+	"rooms": [
+		{"name": "кухня", "note": "кухня", "storages": [{"name_in_case": "на столе", "items": ["чай"]}],
+			"exits": [{"to": "двор", "door": "входная"}],
+			"events": [
+				{"after": 20, "replace": {"чай": "компот"}},
+				{"at": "23:00", "lock_door": "входная", "message": "дверь заперли"}
+			]},
+		{"name": "двор", "note": "зима", "exits": [{"to": "кухня", "door": "входная"}],
+			"events": [{"season": "весна", "set_note": "весна"}]}
+	],
+	"doors": [{"id": "входная", "key": "ключ", "closed_note": "закрыто", "open_note": "открыто"}]
+}`
+
+func TestTimedEvents(t *testing.T) {
+	world, err := Load(strings.NewReader(clockWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	var notes []string
+	world.Events.Subscribe(player, func(text string) {
+		notes = append(notes, text)
+	})
+	door := world.Door("входная")
+
+	world.Tick(5)
+	if door.IsClosed || len(notes) > 0 {
+		t.Fatalf("door closed too early")
+	}
+	world.Tick(5)
+	if !door.IsClosed || !door.Locked || len(notes) != 1 || notes[0] != "дверь заперли" {
+		t.Fatalf("door not locked at 23:00: closed %v, locked %v, notes %v", door.IsClosed, door.Locked, notes)
+	}
+	door.IsClosed, door.Locked = false, false
+	world.Tick(10)
+	if items := world.Start.Storages[0].Items; items[0] != "компот" {
+		t.Errorf("tea not replaced: %v", items)
+	}
+	// до 22:59 следующего дня
+	world.Tick(minutesPerDay - 11)
+	if door.IsClosed || len(notes) != 1 {
+		t.Fatalf("door closed before next night")
+	}
+	world.Tick(1)
+	if !door.IsClosed || len(notes) != 2 {
+		t.Errorf("door not locked on the next night")
+	}
+	if note := world.Rooms["двор"].Note; note != "весна" {
+		t.Errorf("season note not changed: %s", note)
+	}
+	if clock := player.ShowTime(); clock != "день 2, 23:00, весна" {
+		t.Errorf("unexpected clock: %s", clock)
+	}
+}
+
+func TestTimedEventsInvalid(t *testing.T) {
+	for _, events := range []string{
+		`[{"message": "без времени"}]`,
+		`[{"after": 5, "at": "10:00"}]`,
+		`[{"at": "25:00"}]`,
+		`[{"season": "межсезонье"}]`,
+		`[{"after": 5, "lock_door": "нет такой"}]`,
+	} {
+		data := `{"start": "кухня", "rooms": [{"name": "кухня", "events": ` + events + `}]}`
+		if _, err := Load(strings.NewReader(data)); err == nil {
+			t.Errorf("events %s: expected error", events)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package world
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Stats - характеристики игрока или персонажа. Персонаж без здоровья в драках не участвует
+type Stats struct {
+	Health   int `json:"health,omitempty"`
+	Strength int `json:"strength,omitempty"`
+	Armor    int `json:"armor,omitempty"`
+}
+
+// CombatRules - правила драк из описания мира
+type CombatRules struct {
+	// случайность в драках воспроизводима: один и тот же seed даёт одни и те же удары
+	Seed int64
+	// где игрок приходит в себя после гибели, по умолчанию стартовая комната
+	Respawn *Room
+	// при гибели инвентарь остаётся на полу там, где игрок погиб
+	DropOnDeath bool
+}
+
+// countedSource считает выданные числа: сохранение запоминает их количество,
+// и после загрузки генератор продолжает ту же последовательность
+type countedSource struct {
+	rand.Source64
+	draws int
+}
+
+func (source *countedSource) Int63() int64 {
+	source.draws++
+	return source.Source64.Int63()
+}
+
+func (source *countedSource) Uint64() uint64 {
+	source.draws++
+	return source.Source64.Uint64()
+}
+
+func (world *World) setRand(seed int64) {
+	world.Combat.Seed = seed
+	world.source = &countedSource{Source64: rand.NewSource(seed).(rand.Source64)}
+	world.rand = rand.New(world.source)
+}
+
+// draws - сколько чисел генератор выдал с начала игры
+func (world *World) draws() int {
+	if world.source == nil {
+		return 0
+	}
+	return world.source.draws
+}
+
+// rewind возвращает генератор к состоянию после draws выданных чисел.
+// Генератор, уже выдавший ровно столько, не пересоздаётся: это дорого, а решатель
+// восстанавливает состояние на каждом ходу
+func (world *World) rewind(draws int) {
+	if world.source != nil && world.source.draws == draws {
+		return
+	}
+	world.setRand(world.Combat.Seed)
+	for world.source.draws < draws {
+		world.source.Int63()
+	}
+}
+
+// roll - бросок от 0 до n-1
+func (world *World) roll(n int) int {
+	if world.rand == nil {
+		world.setRand(world.Combat.Seed)
+	}
+	return world.rand.Intn(n)
+}
+
+// armor - защита игрока вместе с надетыми предметами
+func (player *Player) armor() int {
+	armor := player.World.PlayerStats.Armor
+	for _, item := range player.Worn() {
+		if itemType, ok := player.World.Items[item]; ok {
+			armor += itemType.Armor
+		}
+	}
+	return armor
+}
+
+func (npc *NPC) defeated() bool {
+	return npc.Stats.Health > 0 && npc.HP <= 0
+}
+
+func (player *Player) healthNote() string {
+	return player.T("здоровье %d/%d", player.Health, player.World.PlayerStats.Health)
+}
+
+func (player *Player) Attack(name string, weapon Item) string {
+	npc := player.CurrentRoom.findNPC(name)
+	if npc == nil {
+		return player.T("здесь нет %s", Name(name))
+	}
+	if npc.Stats.Health == 0 {
+		return player.T("с %s драться нельзя", Name(npc.Name))
+	}
+	damage := player.World.PlayerStats.Strength
+	if weapon != "" {
+		if !slices.Contains(player.Inventory, weapon) {
+			return player.T("нет предмета в инвентаре - %s", weapon)
+		}
+		if itemType, ok := player.World.Items[weapon]; ok {
+			damage += itemType.Damage
+		}
+	}
+	damage = max(damage+player.World.roll(3)-npc.Stats.Armor, 0)
+	npc.HP -= damage
+	player.Dialogue = nil
+	player.publish("атаковал %s", Name(npc.Name))
+	if npc.defeated() {
+		return player.T("вы ударили %s: -%d. %s", Name(npc.Name), damage, npc.defeat(player))
+	}
+	result := player.T("вы промахнулись")
+	if damage > 0 {
+		result = player.T("вы ударили %s: -%d, осталось %d/%d", Name(npc.Name), damage, npc.HP, npc.Stats.Health)
+	}
+	if npc.Stats.Strength > 0 {
+		result += "\n" + npc.strike(player)
+	}
+	return result
+}
+
+// defeat убирает побеждённого персонажа, его добыча остаётся на полу
+func (npc *NPC) defeat(player *Player) string {
+	player.publish("победил %s", Name(npc.Name))
+	result := player.T(npc.Defeat)
+	if npc.Defeat == "" {
+		result = player.T("%s повержен", Name(npc.Name))
+	}
+	if len(npc.Loot) > 0 {
+		floor := player.CurrentRoom.Floor()
+		floor.Items = append(floor.Items, npc.Loot...)
+		result += ". " + player.T("выпало: %s", join(player, npc.Loot))
+	}
+	return result
+}
+
+// strike - удар персонажа по игроку
+func (npc *NPC) strike(player *Player) string {
+	damage := max(npc.Stats.Strength+player.World.roll(2)-player.armor(), 0)
+	player.Health -= damage
+	result := player.T("%s ударил вас: -%d, %s", Name(npc.Name), damage, player.healthNote())
+	if damage == 0 {
+		result = player.T("%s промахнулся", Name(npc.Name))
+	}
+	if player.Health <= 0 {
+		result += "\n" + player.die()
+	}
+	return result
+}
+
+// ambush - враждебные персонажи нападают на вошедшего в комнату
+func (player *Player) ambush() (result []string) {
+	for _, npc := range player.CurrentRoom.NPCs {
+		if !npc.Hostile || npc.defeated() || player.Health <= 0 {
+			continue
+		}
+		room := player.CurrentRoom
+		result = append(result, npc.strike(player))
+		// погибший игрок уже в другой комнате
+		if player.CurrentRoom != room {
+			break
+		}
+	}
+	return result
+}
+
+func (player *Player) die() string {
+	player.publish("погиб")
+	rules := &player.World.Combat
+	if rules.DropOnDeath && len(player.Inventory) > 0 {
+		floor := player.CurrentRoom.Floor()
+		floor.Items = append(floor.Items, player.Inventory...)
+		player.Inventory = player.Inventory[:0]
+	}
+	player.Dialogue = nil
+	// гибель не отменить: журнал очистится, как только команда закончится
+	player.History.forget = true
+	player.Health = player.World.PlayerStats.Health
+	player.CurrentRoom = rules.Respawn
+	player.Visited[rules.Respawn.Name] = true
+	player.publish("пришёл")
+	return player.T("вы погибли и очнулись: %s", Name(rules.Respawn.Note)) + ". " + rules.Respawn.NextRoomsList(player)
+}
+
+// Eat восстанавливает здоровье предметом из инвентаря, предмет при этом пропадает
+func (player *Player) Eat(item Item) string {
+	if !slices.Contains(player.Inventory, item) {
+		return player.T("нет предмета в инвентаре - %s", item)
+	}
+	itemType, ok := player.World.Items[item]
+	if !ok || itemType.Heal == 0 {
+		return player.T("%s нельзя съесть", item)
+	}
+	player.dropFromInventory(item)
+	player.Health = min(player.Health+itemType.Heal, player.World.PlayerStats.Health)
+	player.publish("подкрепился: %s", item)
+	return player.T("вы подкрепились: %s. %s", item, player.healthNote())
+}
+
+func (player *Player) ShowStats() string {
+	return player.T("%s, сила %d, защита %d", player.healthNote(), player.World.PlayerStats.Strength, player.armor())
+}
+
+func checkStats(stats Stats) error {
+	if stats.Health < 0 || stats.Strength < 0 || stats.Armor < 0 {
+		return fmt.Errorf("negative stats")
+	}
+	return nil
+}
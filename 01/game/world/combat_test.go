@@ -0,0 +1,116 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+const combatWorld = `{
+	"start": "двор",
+	"player": {"health": 5, "strength": 1},
+	"combat": {"seed": 7, "drop_on_death": true},
+	"rooms": [
+		{"name": "двор", "note": "двор", "storages": [{"name_in_case": "у забора", "items": ["палка", "сумка", "чай"]}],
+			"exits": [{"to": "подвал"}],
+			"npcs": [{"name": "сторож", "health": 30, "strength": 6}]},
+		{"name": "подвал", "note": "подвал", "exits": [{"to": "двор"}],
+			"npcs": [{"name": "крыса", "health": 3, "strength": 1, "hostile": true, "loot": ["хвост"], "defeat": "крыса убежала"}]}
+	],
+	"items": [
+		{"name": "палка", "damage": 2},
+		{"name": "чай", "heal": 3},
+		{"name": "сумка", "armor": 1, "container": {"name_in_case": "в сумке"}}
+	]
+}`
+
+func TestCombat(t *testing.T) {
+	world, err := Load(strings.NewReader(combatWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	for i, step := range []struct {
+		action func() string
+		answer string
+	}{
+		{func() string { return player.WearItem("сумка") }, "вы надели: сумка"},
+		{func() string { return player.TakeItem("палка") }, "предмет добавлен в инвентарь: палка"},
+		{func() string { return player.TakeItem("чай") }, "предмет добавлен в инвентарь: чай"},
+		{func() string { return player.Attack("крыса", "") }, "здесь нет крыса"},
+		{func() string { return player.Go("подвал") }, "подвал. можно пройти - двор\nкрыса промахнулся"},
+		{func() string { return player.Attack("крыса", "чай") }, "вы ударили крыса: -1, осталось 2/3\nкрыса ударил вас: -1, здоровье 4/5"},
+		{func() string { return player.Attack("крыса", "палка") }, "вы ударили крыса: -3. крыса убежала. выпало: хвост"},
+		{player.ShowStats, "здоровье 4/5, сила 1, защита 1"},
+		{func() string { return player.Eat("палка") }, "палка нельзя съесть"},
+		{func() string { return player.Eat("чай") }, "вы подкрепились: чай. здоровье 5/5"},
+		{player.LookAround, "на полу: хвост. можно пройти - двор"},
+		{func() string { return player.Go("двор") }, "двор. можно пройти - подвал"},
+		{func() string { return player.Attack("сторож", "палка") },
+			"вы ударили сторож: -5, осталось 25/30\nсторож ударил вас: -5, здоровье 0/5\nвы погибли и очнулись: двор. можно пройти - подвал"},
+		// инвентарь остался на полу там, где игрок погиб
+		{func() string { return player.Attack("сторож", "палка") }, "нет предмета в инвентаре - палка"},
+		{player.ShowStats, "здоровье 5/5, сила 1, защита 1"},
+	} {
+		if answer := step.action(); answer != step.answer {
+			t.Errorf("step %d:\n\tresult:   %s\n\texpected: %s", i+1, answer, step.answer)
+		}
+	}
+	if floor := world.Start.Floor(); len(floor.Items) != 1 || floor.Items[0] != "палка" {
+		t.Errorf("inventory not dropped on death: %v", floor.Items)
+	}
+}
+
+func TestCombatState(t *testing.T) {
+	world, err := Load(strings.NewReader(combatWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	saved := world.SaveState(player)
+	player.Go("подвал")
+	for i := 0; i < 10 && world.Rooms["подвал"].findNPC("крыса") != nil; i++ {
+		player.Attack("крыса", "")
+	}
+	if err = world.RestoreState(player, saved); err != nil {
+		t.Fatal(err)
+	}
+	if world.Rooms["подвал"].findNPC("крыса") == nil || player.Health != 5 {
+		t.Errorf("defeated npc or health not restored")
+	}
+}
+
+func TestCombatReplay(t *testing.T) {
+	world, err := Load(strings.NewReader(combatWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Attack("сторож", "")
+	saved := world.SaveState(player)
+	attack := func() (answers []string) {
+		for i := 0; i < 3; i++ {
+			answers = append(answers, player.Attack("сторож", ""))
+		}
+		return answers
+	}
+	first := attack()
+	if err = world.RestoreState(player, saved); err != nil {
+		t.Fatal(err)
+	}
+	// после загрузки броски те же, что и без неё
+	if second := attack(); strings.Join(first, "\n") != strings.Join(second, "\n") {
+		t.Errorf("rolls differ after restore:\n%s\n---\n%s", strings.Join(first, "\n"), strings.Join(second, "\n"))
+	}
+
+	// гибель из журнала не отменить
+	player = world.NewPlayer()
+	before := world.SaveState(player)
+	answer := player.Attack("сторож", "")
+	if !strings.Contains(answer, "вы погибли") {
+		t.Fatalf("player survived: %s", answer)
+	}
+	player.Record("атаковать сторож", "", before)
+	if answer := player.Undo(); answer != "нечего отменять" {
+		t.Errorf("death undone: %s", answer)
+	}
+}
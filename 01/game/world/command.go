@@ -0,0 +1,102 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command - команда игрока. Обработчик вызывается, только если число аргументов подходит
+type Command struct {
+	Name    string
+	Aliases []string
+	// названия команды на других языках, они работают как синонимы и показываются в помощи
+	Names map[Locale]string
+	// обязательные аргументы, их названия выводятся в подсказках
+	Args []string
+	// необязательные аргументы после обязательных
+	Optional []string
+	// команда принимает любой текст после обязательных аргументов
+	Rest bool
+	// команда не занимает игрового времени и не попадает в журнал действий, например сохранение
+	Instant bool
+	Help    string
+	Handler func(player *Player, args []string) string
+}
+
+// Usage - как вызывать команду, на языке игрока
+func (command *Command) Usage(player *Player) string {
+	usage := command.Name
+	if name, ok := command.Names[player.Locale]; ok {
+		usage = name
+	}
+	for _, arg := range command.Args {
+		usage += " <" + player.T(arg) + ">"
+	}
+	for _, arg := range command.Optional {
+		usage += " [<" + player.T(arg) + ">]"
+	}
+	if command.Rest {
+		usage += " ..."
+	}
+	return usage
+}
+
+func (command *Command) Run(player *Player, args []string) string {
+	if len(args) < len(command.Args) {
+		return player.T("не хватает аргументов: %s", command.Usage(player))
+	}
+	if len(args) > len(command.Args)+len(command.Optional) && !command.Rest {
+		return player.T("слишком много аргументов: %s", command.Usage(player))
+	}
+	return command.Handler(player, args)
+}
+
+// Commands - набор команд с поиском по названию и синонимам
+type Commands struct {
+	list   []*Command
+	byName map[string]*Command
+}
+
+func (commands *Commands) Register(command *Command) error {
+	if command.Name == "" {
+		return fmt.Errorf("command without name")
+	}
+	if commands.byName == nil {
+		commands.byName = make(map[string]*Command)
+	}
+	names := append([]string{command.Name}, command.Aliases...)
+	for _, name := range command.Names {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if _, ok := commands.byName[name]; ok {
+			return fmt.Errorf("command %q already registered", name)
+		}
+	}
+	for _, name := range names {
+		commands.byName[name] = command
+	}
+	commands.list = append(commands.list, command)
+	return nil
+}
+
+func (commands *Commands) Find(name string) *Command {
+	return commands.byName[name]
+}
+
+// List возвращает команды в порядке регистрации
+func (commands *Commands) List() []*Command {
+	return commands.list
+}
+
+func (commands *Commands) HelpList(player *Player) string {
+	lines := make([]string, 0, len(commands.list))
+	for _, command := range commands.list {
+		line := command.Usage(player)
+		if command.Help != "" {
+			line += " - " + player.T(command.Help)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+package world
+
+import "slices"
+
+// Condition - декларативное условие над состоянием игрока.
+// Все заданные поля должны выполняться одновременно, пустое условие выполнено всегда
+type Condition struct {
+	// в инвентаре есть все перечисленные предметы
+	HasItems []Item `json:"has_items,omitempty"`
+	// на игроке надеты все перечисленные предметы
+	Wears []Item `json:"wears,omitempty"`
+	// игрок находится в комнате с таким названием
+	InRoom string `json:"in_room,omitempty"`
+	// игрок хоть раз побывал в комнате
+	Visited string `json:"visited,omitempty"`
+	// дверь с таким ID открыта
+	DoorOpen string `json:"door_open,omitempty"`
+	// на игроке есть что-то светящееся
+	Light bool `json:"light,omitempty"`
+	// надетые вещи греют хотя бы на столько
+	Warmth int `json:"warmth,omitempty"`
+	// у игрока есть отметка, например после разговора
+	Flag string `json:"flag,omitempty"`
+	// задание с таким названием выполнено
+	QuestDone string `json:"quest_done,omitempty"`
+	// условие никогда не выполняется, для заданий-заглушек
+	Never bool `json:"never,omitempty"`
+}
+
+func (cond Condition) Check(player *Player) bool {
+	if cond.Never {
+		return false
+	}
+	for _, item := range cond.HasItems {
+		if !slices.Contains(player.Inventory, item) {
+			return false
+		}
+	}
+	if cond.Flag != "" && !player.Flags[cond.Flag] {
+		return false
+	}
+	for _, item := range cond.Wears {
+		if !player.wears(item) {
+			return false
+		}
+	}
+	if cond.QuestDone != "" {
+		if quest := player.World.quest(cond.QuestDone); quest == nil || !quest.done(player) {
+			return false
+		}
+	}
+	if cond.Light && !player.hasLight() {
+		return false
+	}
+	if player.warmth() < cond.Warmth {
+		return false
+	}
+	if cond.InRoom != "" && (player.CurrentRoom == nil || player.CurrentRoom.Name != cond.InRoom) {
+		return false
+	}
+	if cond.Visited != "" && !player.Visited[cond.Visited] {
+		return false
+	}
+	if cond.DoorOpen != "" {
+		if door := player.World.Door(cond.DoorOpen); door == nil || door.IsClosed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package world
+
+import "slices"
+
+// findContainer ищет хранилище, с которым может работать игрок: хранилище комнаты
+// или предмет-контейнер, надетый, лежащий на виду либо в инвентаре, в том числе внутри
+// другого открытого контейнера
+func (player *Player) findContainer(name string) *Storage {
+	for _, storage := range player.CurrentRoom.Storages {
+		if storage.Name == name {
+			return storage
+		}
+	}
+	container, ok := player.World.Containers[Item(name)]
+	if !ok {
+		return nil
+	}
+	if player.wears(Item(name)) || player.World.within(player.Inventory, Item(name)) {
+		return container
+	}
+	for _, storage := range player.CurrentRoom.Storages {
+		if !storage.Closed && player.World.within(storage.Items, Item(name)) {
+			return container
+		}
+	}
+	return nil
+}
+
+// within ищет предмет среди items и в открытых контейнерах, которые в них лежат.
+// Каждый контейнер существует в мире в одном экземпляре, поэтому вложенность без циклов
+func (world *World) within(items []Item, item Item) bool {
+	for _, candidate := range items {
+		if candidate == item {
+			return true
+		}
+		if container, ok := world.Containers[candidate]; ok && !container.Closed && world.within(container.Items, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// inside - лежит ли предмет внутри контейнера, в том числе во вложенных и закрытых
+func (world *World) inside(item, container Item) bool {
+	storage, ok := world.Containers[container]
+	if !ok {
+		return false
+	}
+	for _, candidate := range storage.Items {
+		if candidate == item || world.inside(item, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenStorage открывает хранилище или дверь текущей комнаты
+func (player *Player) OpenStorage(name string) string {
+	storage := player.findContainer(name)
+	if storage == nil {
+		if door := player.CurrentRoom.findDoor(name); door != nil {
+			return player.openDoor(door)
+		}
+		return player.T("нет такого")
+	}
+	if !storage.Closable {
+		return player.T("нельзя открыть %s", Name(name))
+	}
+	if !storage.Closed {
+		return player.T("%s уже открыт", Name(name))
+	}
+	if storage.Locked {
+		if !slices.Contains(player.Inventory, storage.Key) {
+			return player.T("%s заперт", Name(name))
+		}
+		storage.Locked = false
+	}
+	storage.Closed = false
+	player.publish("открыл %s", Name(name))
+	return player.T("%s открыт", Name(name))
+}
+
+func (player *Player) CloseStorage(name string) string {
+	storage := player.findContainer(name)
+	if storage == nil {
+		if door := player.CurrentRoom.findDoor(name); door != nil {
+			return player.closeDoor(door)
+		}
+		return player.T("нет такого")
+	}
+	if !storage.Closable {
+		return player.T("нельзя закрыть %s", Name(name))
+	}
+	if storage.Closed {
+		return player.T("%s уже закрыт", Name(name))
+	}
+	storage.Closed = true
+	player.publish("закрыл %s", Name(name))
+	return player.T("%s закрыт", name)
+}
+
+// PutItem перекладывает предмет из инвентаря в хранилище
+func (player *Player) PutItem(item Item, name string) string {
+	i := slices.Index(player.Inventory, item)
+	if i < 0 {
+		return player.T("нет предмета в инвентаре - %s", item)
+	}
+	// контейнер нельзя положить ни в себя, ни в то, что лежит у него внутри
+	if string(item) == name || player.World.inside(Item(name), item) {
+		return player.T("нельзя положить предмет в самого себя")
+	}
+	storage := player.findContainer(name)
+	if storage == nil {
+		return player.T("нет такого")
+	}
+	if storage.Closed {
+		return player.T("%s закрыт", name)
+	}
+	// инвентарь и есть содержимое надетых контейнеров, предмет уже лежит там
+	if player.wears(Item(name)) {
+		return player.T("вы положили %s в %s", item, name)
+	}
+	if refusal := player.refusal(storage, storage.Items, item); refusal != "" {
+		return refusal
+	}
+	player.Inventory = deleteItem(player.Inventory, i)
+	storage.Items = append(storage.Items, item)
+	player.publish("положил %s в %s", item, Name(name))
+	return player.T("вы положили %s в %s", item, name)
+}
+
+// TakeItemFrom достаёт предмет из конкретного хранилища, в том числе из вложенного контейнера
+func (player *Player) TakeItemFrom(item Item, name string) string {
+	storage := player.findContainer(name)
+	if storage == nil {
+		return player.T("нет такого")
+	}
+	if storage.Closed {
+		return player.T("%s закрыт", name)
+	}
+	i := slices.Index(storage.Items, item)
+	if i < 0 {
+		return player.T("нет такого")
+	}
+	if refusal, ok := player.canCarry(item); !ok {
+		return refusal
+	}
+	if answer, blocked := player.blockedBy(player.CurrentRoom, OnTake, item, ""); blocked {
+		return answer
+	}
+	storage.Items = deleteItem(storage.Items, i)
+	player.Inventory = append(player.Inventory, item)
+	player.publish("взял %s из %s", item, Name(name))
+	return withHooks(player.T("предмет добавлен в инвентарь: %s", item), player.runHooks(player.CurrentRoom, OnTake, item, ""))
+}
+
+// describeItem показывает содержимое открытых предметов-контейнеров
+func (player *Player) describeItem(item Item) string {
+	return renderItems(player.itemViews(&Storage{Items: []Item{item}}), plain)
+}
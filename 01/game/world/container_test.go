@@ -0,0 +1,102 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+const bedroomWorld = `{
+	"start": "спальня",
+	"rooms": [{
+		"name": "спальня",
+		"storages": [
+			{"name": "пол", "name_in_case": "на полу", "items": ["рюкзак", "ключик", "коробка"]},
+			{"name": "шкаф", "name_in_case": "в шкафу", "items": ["свитер"], "closable": true, "closed": true},
+			{"name": "сейф", "name_in_case": "в сейфе", "items": ["деньги"],
+				"closable": true, "closed": true, "locked": true, "key": "ключик"}
+		]
+	}],
+	"items": [
+		{"name": "рюкзак", "container": {"name_in_case": "в рюкзаке", "capacity": 2}},
+		{"name": "коробка", "container": {"name_in_case": "в коробке", "items": ["нитки"]}},
+		{"name": "сумка", "slot": "плечо", "container": {"name_in_case": "в сумке"}}
+	]
+}`
+
+func TestContainers(t *testing.T) {
+	world, err := Load(strings.NewReader(bedroomWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Equipment["плечо"] = "сумка"
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{player.LookAround, "на полу: рюкзак, ключик, коробка (нитки), шкаф закрыт, сейф закрыт. можно пройти - "},
+		{func() string { return player.TakeItem("свитер") }, "нет такого"},
+		{func() string { return player.TakeItemFrom("свитер", "шкаф") }, "шкаф закрыт"},
+		{func() string { return player.OpenStorage("шкаф") }, "шкаф открыт"},
+		{func() string { return player.OpenStorage("шкаф") }, "шкаф уже открыт"},
+		{func() string { return player.TakeItemFrom("свитер", "шкаф") }, "предмет добавлен в инвентарь: свитер"},
+		{func() string { return player.OpenStorage("сейф") }, "сейф заперт"},
+		{func() string { return player.TakeItem("ключик") }, "предмет добавлен в инвентарь: ключик"},
+		{func() string { return player.OpenStorage("сейф") }, "сейф открыт"},
+		{func() string { return player.TakeItemFrom("нитки", "коробка") }, "предмет добавлен в инвентарь: нитки"},
+		{func() string { return player.PutItem("свитер", "рюкзак") }, "вы положили свитер в рюкзак"},
+		{func() string { return player.PutItem("нитки", "рюкзак") }, "вы положили нитки в рюкзак"},
+		{func() string { return player.PutItem("ключик", "рюкзак") }, "в рюкзаке нет места"},
+		{func() string { return player.PutItem("ключик", "ключик") }, "нельзя положить предмет в самого себя"},
+		{func() string { return player.CloseStorage("полка") }, "нет такого"},
+		{func() string { return player.CloseStorage("коробка") }, "нельзя закрыть коробка"},
+		{player.LookAround, "на полу: рюкзак (свитер, нитки), коробка, в сейфе: деньги. можно пройти - "},
+		{func() string { return player.WearItem("рюкзак") }, "вы надели: рюкзак"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if len(player.Inventory) != 3 {
+		t.Errorf("backpack contents are not in inventory: %v", player.Inventory)
+	}
+}
+
+func TestNestedContainers(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "спальня",
+		"rooms": [{"name": "спальня", "storages": [{"name_in_case": "на полу", "items": ["сундук", "сумка"]}]}],
+		"items": [
+			{"name": "сундук", "container": {"name_in_case": "в сундуке", "items": ["шкатулка"]}},
+			{"name": "шкатулка", "container": {"name_in_case": "в шкатулке", "items": ["кольцо"]}},
+			{"name": "сумка", "slot": "плечо", "container": {"name_in_case": "в сумке"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.WearItem("сумка") }, "вы надели: сумка"},
+		{func() string { return player.TakeItemFrom("кольцо", "шкатулка") }, "предмет добавлен в инвентарь: кольцо"},
+		{func() string { return player.TakeItemFrom("шкатулка", "сундук") }, "предмет добавлен в инвентарь: шкатулка"},
+		{func() string { return player.TakeItem("сундук") }, "предмет добавлен в инвентарь: сундук"},
+		{func() string { return player.PutItem("шкатулка", "сундук") }, "вы положили шкатулка в сундук"},
+		{func() string { return player.PutItem("кольцо", "шкатулка") }, "вы положили кольцо в шкатулка"},
+		// надетая сумка и есть инвентарь: кольцо остаётся при игроке
+		{func() string { return player.TakeItemFrom("кольцо", "шкатулка") }, "предмет добавлен в инвентарь: кольцо"},
+		{func() string { return player.PutItem("кольцо", "сумка") }, "вы положили кольцо в сумка"},
+		{player.ShowInventory, "в инвентаре: сундук (шкатулка), кольцо"},
+		// сундук лежит в шкатулке внутри себя самого
+		{func() string { return player.PutItem("сундук", "шкатулка") }, "нельзя положить предмет в самого себя"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package world
+
+import "fmt"
+
+// Effect - изменения мира, которые описываются в файле мира: их вызывают
+// события по расписанию и обработчики комнат
+type Effect struct {
+	// сообщение игрокам в комнате
+	Message           string `json:"message,omitempty"`
+	SetNote           string `json:"set_note,omitempty"`
+	SetLookAroundNote string `json:"set_look_around_note,omitempty"`
+	// закрыть и запереть дверь или открыть её, по ID
+	LockDoor   string `json:"lock_door,omitempty"`
+	UnlockDoor string `json:"unlock_door,omitempty"`
+	// заменить предмет в хранилищах комнаты, например чай на остывший
+	Replace map[Item]Item `json:"replace,omitempty"`
+}
+
+func (effect *Effect) apply(world *World, room *Room) {
+	if effect.SetNote != "" {
+		room.Note = effect.SetNote
+	}
+	if effect.SetLookAroundNote != "" {
+		room.LookAroundNote = effect.SetLookAroundNote
+	}
+	if door := world.Door(effect.LockDoor); door != nil {
+		door.IsClosed = true
+		door.Locked = door.Key != ""
+	}
+	if door := world.Door(effect.UnlockDoor); door != nil {
+		door.IsClosed = false
+		door.Locked = false
+	}
+	for from, to := range effect.Replace {
+		for _, storage := range room.Storages {
+			for i, item := range storage.Items {
+				if item == from {
+					storage.Items[i] = to
+				}
+			}
+		}
+	}
+	if effect.Message != "" {
+		world.Events.Publish(Event{Room: room, Text: effect.Message})
+	}
+}
+
+func (world *World) checkEffect(effect Effect) error {
+	for _, id := range []string{effect.LockDoor, effect.UnlockDoor} {
+		if id != "" && world.Door(id) == nil {
+			return fmt.Errorf("unknown door %q", id)
+		}
+	}
+	return nil
+}
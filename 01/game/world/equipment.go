@@ -0,0 +1,136 @@
+package world
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultSlot - куда надеваются предметы-контейнеры, если слот в описании мира не указан
+const DefaultSlot = "спина"
+
+// Worn - надетые предметы в порядке слотов
+func (player *Player) Worn() []Item {
+	slots := make([]string, 0, len(player.Equipment))
+	for slot := range player.Equipment {
+		slots = append(slots, slot)
+	}
+	slices.Sort(slots)
+	worn := make([]Item, 0, len(slots))
+	for _, slot := range slots {
+		worn = append(worn, player.Equipment[slot])
+	}
+	return worn
+}
+
+func (player *Player) wears(item Item) bool {
+	return slices.Contains(player.Worn(), item)
+}
+
+// slot - куда надевается предмет, пустая строка у предметов, которые не надеваются
+func (world *World) slot(item Item) string {
+	if itemType, ok := world.Items[item]; ok {
+		return itemType.Slot
+	}
+	return ""
+}
+
+// WearItem надевает предмет, лежащий в комнате или в инвентаре
+func (player *Player) WearItem(item Item) string {
+	storage, i, inRoom := player.CurrentRoom.findItem(item)
+	if !inRoom && !slices.Contains(player.Inventory, item) {
+		return player.T("нет такого")
+	}
+	slot := player.World.slot(item)
+	if slot == "" {
+		return player.T("нельзя надеть %s", item)
+	}
+	if worn, ok := player.Equipment[slot]; ok {
+		return player.T("сначала снимите %s", worn)
+	}
+	if inRoom {
+		storage.Items = deleteItem(storage.Items, i)
+	} else {
+		player.dropFromInventory(item)
+	}
+	player.Equipment[slot] = item
+	// то, что лежало в надетом контейнере, теперь носится с собой
+	if container, ok := player.World.Containers[item]; ok {
+		player.Inventory = append(player.Inventory, container.Items...)
+		container.Items = nil
+	}
+	player.publish("надел %s", item)
+	return player.T("вы надели: %s", item)
+}
+
+// TakeOff снимает предмет в инвентарь. Если снятый контейнер был последним,
+// вещи из инвентаря возвращаются в него, а сам он кладётся на пол
+func (player *Player) TakeOff(item Item) string {
+	slot := player.World.slot(item)
+	if slot == "" || player.Equipment[slot] != item {
+		return player.T("на вас нет %s", item)
+	}
+	delete(player.Equipment, slot)
+	if !player.HasBackpack() {
+		if container, ok := player.World.Containers[item]; ok {
+			container.Items = append(container.Items, player.Inventory...)
+			player.Inventory = player.Inventory[:0]
+		}
+		floor := player.CurrentRoom.Floor()
+		floor.Items = append(floor.Items, item)
+		player.publish("снял %s", item)
+		return player.T("вы сняли и положили на пол: %s", item)
+	}
+	// снятое должно поместиться в оставшиеся контейнеры вместе со всем инвентарём
+	limits, _ := player.carryLimits()
+	if refusal := player.refusal(&limits, player.Inventory, item); refusal != "" {
+		player.Equipment[slot] = item
+		return refusal
+	}
+	player.Inventory = append(player.Inventory, item)
+	player.publish("снял %s", item)
+	return player.T("вы сняли: %s", item)
+}
+
+// hasLight - светит ли что-то из надетого
+func (player *Player) hasLight() bool {
+	for _, item := range player.Worn() {
+		if itemType, ok := player.World.Items[item]; ok && itemType.Light {
+			return true
+		}
+	}
+	return false
+}
+
+// warmth - сколько тепла дают надетые вещи
+func (player *Player) warmth() int {
+	warmth := 0
+	for _, item := range player.Worn() {
+		if itemType, ok := player.World.Items[item]; ok {
+			warmth += itemType.Warmth
+		}
+	}
+	return warmth
+}
+
+func (player *Player) ShowEquipment() string {
+	if len(player.Equipment) == 0 {
+		return player.T("на вас ничего не надето")
+	}
+	slots := make([]string, 0, len(player.Equipment))
+	for slot := range player.Equipment {
+		slots = append(slots, slot)
+	}
+	slices.Sort(slots)
+	lines := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		lines = append(lines, player.translate(slot)+": "+player.translate(string(player.Equipment[slot])))
+	}
+	result := player.T("надето: %s", strings.Join(lines, ", "))
+	if warmth := player.warmth(); warmth > 0 {
+		result += ", " + player.T("тепло %d", warmth)
+	}
+	if player.hasLight() {
+		result += ", " + player.T("есть свет")
+	}
+	return result
+}
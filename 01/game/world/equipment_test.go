@@ -0,0 +1,83 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const wardrobeWorld = `{
+	"start": "прихожая",
+	"rooms": [
+		{"name": "прихожая", "storages": [{"name_in_case": "на вешалке", "items": ["рюкзак", "шапка", "фонарь", "перчатки", "камень"]}],
+			"exits": [
+				{"to": "подвал", "if": {"light": true}, "refusal": "в подвале темно"},
+				{"to": "двор", "if": {"warmth": 2}, "refusal": "на улице мороз"}
+			]},
+		{"name": "подвал", "exits": [{"to": "прихожая"}]},
+		{"name": "двор", "exits": [{"to": "прихожая"}]}
+	],
+	"items": [
+		{"name": "рюкзак", "container": {"name_in_case": "в рюкзаке", "capacity": 3}},
+		{"name": "шапка", "slot": "голова", "warmth": 2},
+		{"name": "фонарь", "slot": "руки", "light": true},
+		{"name": "перчатки", "slot": "руки", "warmth": 1}
+	]
+}`
+
+func TestEquipment(t *testing.T) {
+	world, err := Load(strings.NewReader(wardrobeWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.WearItem("камень") }, "нельзя надеть камень"},
+		{func() string { return player.WearItem("сапоги") }, "нет такого"},
+		{func() string { return player.Go("подвал") }, "в подвале темно"},
+		{func() string { return player.WearItem("рюкзак") }, "вы надели: рюкзак"},
+		{func() string { return player.TakeItem("фонарь") }, "предмет добавлен в инвентарь: фонарь"},
+		{func() string { return player.WearItem("фонарь") }, "вы надели: фонарь"},
+		{func() string { return player.WearItem("перчатки") }, "сначала снимите фонарь"},
+		{func() string { return player.Go("двор") }, "на улице мороз"},
+		{func() string { return player.WearItem("шапка") }, "вы надели: шапка"},
+		{player.ShowEquipment, "надето: голова: шапка, руки: фонарь, спина: рюкзак, тепло 2, есть свет"},
+		{func() string { return player.TakeOff("перчатки") }, "на вас нет перчатки"},
+		{func() string { return player.TakeOff("камень") }, "на вас нет камень"},
+		{func() string { return player.TakeOff("фонарь") }, "вы сняли: фонарь"},
+		{func() string { return player.TakeItem("перчатки") }, "предмет добавлен в инвентарь: перчатки"},
+		{func() string { return player.TakeItem("камень") }, "предмет добавлен в инвентарь: камень"},
+		// в рюкзаке три места, а снять шапку значит положить её туда четвёртой
+		{func() string { return player.TakeOff("шапка") }, "в рюкзаке нет места"},
+		{func() string { return player.TakeOff("рюкзак") }, "вы сняли и положили на пол: рюкзак"},
+		{player.ShowEquipment, "надето: голова: шапка, тепло 2"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if len(player.Inventory) != 0 || !slices.Equal(world.Containers["рюкзак"].Items, []Item{"фонарь", "перчатки", "камень"}) {
+		t.Errorf("inventory %v, backpack %v", player.Inventory, world.Containers["рюкзак"].Items)
+	}
+
+	state := world.SaveState(player)
+	player.WearItem("рюкзак")
+	if err := world.RestoreState(player, state); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(player.Worn(), []Item{"шапка"}) {
+		t.Errorf("restored equipment: %v", player.Worn())
+	}
+	state.Player.Worn = []Item{"камень"}
+	if err := world.RestoreState(player, state); err == nil {
+		t.Error("expected error for worn stone")
+	}
+
+	if _, err := Load(strings.NewReader(`{"start": "a", "rooms": [{"name": "a"}], "items": [{"name": "шлем", "armor": 1}]}`)); err == nil {
+		t.Error("expected error for armor without slot")
+	}
+}
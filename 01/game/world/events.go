@@ -0,0 +1,55 @@
+package world
+
+// Event - действие игрока, которое видят остальные игроки в той же комнате.
+// Text - русский шаблон сообщения, каждый получатель видит его на своём языке
+type Event struct {
+	Room  *Room
+	Actor *Player
+	Text  string
+	Args  []any
+}
+
+// Bus доставляет события подписанным игрокам.
+// Публикация происходит под блокировкой мира, поэтому доставка не должна блокироваться
+type Bus struct {
+	subscribers map[*Player]func(text string)
+}
+
+func (bus *Bus) Subscribe(player *Player, deliver func(text string)) {
+	if bus.subscribers == nil {
+		bus.subscribers = make(map[*Player]func(text string))
+	}
+	bus.subscribers[player] = deliver
+}
+
+func (bus *Bus) Unsubscribe(player *Player) {
+	delete(bus.subscribers, player)
+}
+
+func (bus *Bus) Publish(event Event) {
+	for player, deliver := range bus.subscribers {
+		if player != event.Actor && player.CurrentRoom == event.Room {
+			deliver(player.T(event.Text, event.Args...))
+		}
+	}
+}
+
+// Tell доставляет сообщение только самому игроку, например о выполненном задании
+func (bus *Bus) Tell(player *Player, text string) {
+	if deliver, ok := bus.subscribers[player]; ok {
+		deliver(text)
+	}
+}
+
+// publish сообщает остальным игрокам в текущей комнате о действии игрока
+func (player *Player) publish(format string, args ...any) {
+	if player.World == nil {
+		return
+	}
+	player.World.Events.Publish(Event{
+		Room:  player.CurrentRoom,
+		Actor: player,
+		Text:  "%s " + format,
+		Args:  append([]any{player.Name}, args...),
+	})
+}
@@ -0,0 +1,135 @@
+package world
+
+import (
+	"slices"
+	"strings"
+)
+
+const nothingSpecial = "ничего особенного"
+
+// Examine показывает подробное описание комнаты, двери, хранилища или предмета.
+// place уточняет, в каком хранилище искать предмет, если он лежит в нескольких
+func (player *Player) Examine(name, place string) string {
+	room := player.CurrentRoom
+	if name == "" {
+		if room.Description == "" {
+			return player.LookAround()
+		}
+		return player.T(room.Description)
+	}
+	if place != "" {
+		storage := player.findPlace(place)
+		if storage == nil {
+			return player.T("нет такого")
+		}
+		if storage.Closed {
+			return player.T("%s закрыт", Name(place))
+		}
+		if !slices.Contains(storage.Items, Item(name)) {
+			return player.T("нет такого")
+		}
+		return player.describe(Item(name), storage)
+	}
+	if door := room.findDoor(name); door != nil {
+		return player.examineDoor(door)
+	}
+	if storage := player.findContainer(name); storage != nil {
+		description := storage.Description
+		if itemType, ok := player.World.Items[Item(name)]; ok && itemType.Description != "" {
+			description = itemType.Description
+		}
+		return player.examineStorage(storage, description)
+	}
+	item := Item(name)
+	var places []*Storage
+	for _, storage := range room.Storages {
+		if !storage.Closed && slices.Contains(storage.Items, item) {
+			places = append(places, storage)
+		}
+	}
+	switch {
+	case len(places) > 1:
+		where := make([]string, 0, len(places))
+		for _, storage := range places {
+			if storage.Name != "" {
+				where = append(where, storage.Name)
+			} else {
+				where = append(where, storage.NameInCase)
+			}
+		}
+		return player.T("уточните, где: %s", join(player, where))
+	case len(places) == 1:
+		return player.describe(item, places[0])
+	case slices.Contains(player.Inventory, item) || player.wears(item):
+		return player.describe(item, nil)
+	}
+	return player.T("нет такого")
+}
+
+// findPlace ищет место, где лежит предмет: хранилище по имени или безымянное
+// по name_in_case, целиком ("на стуле") или без предлога ("стуле")
+func (player *Player) findPlace(place string) *Storage {
+	if storage := player.findContainer(place); storage != nil {
+		return storage
+	}
+	for _, storage := range player.CurrentRoom.Storages {
+		if storage.Name == "" && (storage.NameInCase == place || placeWord(storage) == place) {
+			return storage
+		}
+	}
+	return nil
+}
+
+// placeWord - последнее слово name_in_case, так его оставляет разбор команды
+func placeWord(storage *Storage) string {
+	words := strings.Fields(storage.NameInCase)
+	if len(words) == 0 {
+		return ""
+	}
+	return words[len(words)-1]
+}
+
+// describe - описание предмета. Хранилище может описать лежащий в нём предмет по-своему
+func (player *Player) describe(item Item, storage *Storage) string {
+	if storage != nil {
+		if description, ok := storage.Descriptions[item]; ok {
+			return player.T(description)
+		}
+	}
+	if itemType, ok := player.World.Items[item]; ok && itemType.Description != "" {
+		return player.T(itemType.Description)
+	}
+	return player.T(nothingSpecial)
+}
+
+func (player *Player) examineDoor(door *Door) string {
+	description := nothingSpecial
+	if door.Description != "" {
+		description = door.Description
+	}
+	result := player.T(description)
+	if state := door.States[door.IsClosed]; state != "" {
+		result += ", " + player.T(state)
+	}
+	return result
+}
+
+// examineStorage описывает хранилище, а в открытом находит спрятанные предметы
+func (player *Player) examineStorage(storage *Storage, description string) string {
+	if description == "" {
+		description = nothingSpecial
+	}
+	result := player.T(description)
+	if storage.Closed {
+		return result + ", " + player.T("%s закрыт", Name(storage.Name))
+	}
+	if len(storage.Hidden) > 0 {
+		result += ". " + player.T("вы нашли: %s", join(player, storage.Hidden))
+		storage.Items = append(storage.Items, storage.Hidden...)
+		for _, item := range storage.Hidden {
+			player.publish("нашёл %s в %s", item, Name(storage.Name))
+		}
+		storage.Hidden = nil
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const studyWorld = `{
+	"start": "кабинет",
+	"rooms": [
+		{"name": "кабинет", "note": "кабинет", "description": "тесный кабинет с окном во двор",
+			"storages": [
+				{"name": "стол", "name_in_case": "на столе", "items": ["ключ", "лампа"],
+					"description": "старый дубовый стол", "hidden": ["записка"],
+					"descriptions": {"ключ": "ключ на столе покрыт пылью"}},
+				{"name": "полка", "name_in_case": "на полке", "items": ["ключ", "кошелёк"]},
+				{"name": "ящик", "name_in_case": "в ящике", "items": ["монета"], "closable": true, "closed": true}
+			],
+			"exits": [{"to": "двор", "door": "входная"}]},
+		{"name": "двор", "note": "двор", "exits": [{"to": "кабинет", "door": "входная"}]}
+	],
+	"doors": [{"id": "входная", "closed": true, "closed_note": "дверь закрыта", "open_note": "дверь открыта",
+		"description": "дверь, обитая железом"}],
+	"items": [
+		{"name": "ключ", "description": "маленький медный ключ"},
+		{"name": "записка", "description": "записка: ключ от двери на полке"},
+		{"name": "кошелёк", "container": {"name": "кошелёк", "name_in_case": "в кошельке", "hidden": ["монета"]}}
+	]
+}`
+
+func TestExamine(t *testing.T) {
+	world, err := Load(strings.NewReader(studyWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	start := world.SaveState(player)
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.Examine("", "") }, "тесный кабинет с окном во двор"},
+		{func() string { return player.Examine("дверь", "") }, "дверь, обитая железом, дверь закрыта"},
+		{func() string { return player.Examine("ключ", "") }, "уточните, где: стол, полка"},
+		{func() string { return player.Examine("ключ", "стол") }, "ключ на столе покрыт пылью"},
+		{func() string { return player.Examine("ключ", "полка") }, "маленький медный ключ"},
+		{func() string { return player.Examine("лампа", "") }, "ничего особенного"},
+		{func() string { return player.Examine("лампа", "полка") }, "нет такого"},
+		{func() string { return player.Examine("записка", "") }, "нет такого"},
+		{func() string { return player.Examine("ящик", "") }, "ничего особенного, ящик закрыт"},
+		{func() string { return player.Examine("монета", "ящик") }, "ящик закрыт"},
+		{func() string { return player.Examine("стол", "") }, "старый дубовый стол. вы нашли: записка"},
+		{func() string { return player.Examine("стол", "") }, "старый дубовый стол"},
+		{func() string { return player.Examine("записка", "") }, "записка: ключ от двери на полке"},
+		{func() string { return player.Examine("кошелёк", "") }, "ничего особенного. вы нашли: монета"},
+		{player.LookAround, "на столе: ключ, лампа, записка, на полке: ключ, кошелёк (монета), ящик закрыт. можно пройти - двор"},
+		{func() string { return player.Examine("шкаф", "") }, "нет такого"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+
+	// спрятанное в начале игры снова спрятано после загрузки начального состояния
+	if err := world.RestoreState(player, start); err != nil {
+		t.Fatal(err)
+	}
+	if answer := player.Examine("записка", ""); answer != "нет такого" {
+		t.Errorf("hidden item visible after restore: %s", answer)
+	}
+	if answer := player.Examine("кошелёк", ""); answer != "ничего особенного. вы нашли: монета" {
+		t.Errorf("container hidden items lost after restore: %s", answer)
+	}
+
+	player.Locale = English
+	if answer := player.Examine("лампа", ""); answer != "nothing special" {
+		t.Errorf("unexpected english answer: %s", answer)
+	}
+}
+
+func TestExamineUnnamedPlaces(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "кухня",
+		"rooms": [{"name": "кухня", "storages": [
+			{"name_in_case": "на столе", "items": ["ключ"]},
+			{"name_in_case": "на стуле", "items": ["ключ"]}
+		]}],
+		"items": [{"name": "ключ", "description": "маленький медный ключ"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.Examine("ключ", "") }, "уточните, где: на столе, на стуле"},
+		{func() string { return player.Examine("ключ", "на стуле") }, "маленький медный ключ"},
+		{func() string { return player.Examine("ключ", "стуле") }, "маленький медный ключ"},
+		{func() string { return player.Examine("ключ", "полке") }, "нет такого"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if !slices.Contains(player.TargetNames(), "стуле") {
+		t.Errorf("unnamed storage can't be typed: %v", player.TargetNames())
+	}
+}
+
+func TestSolveHidden(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "кабинет",
+		"rooms": [{"name": "кабинет", "storages": [
+			{"name": "стол", "name_in_case": "на столе", "items": ["рюкзак"], "hidden": ["записка"]}
+		]}],
+		"items": [{"name": "рюкзак", "container": {"name_in_case": "в рюкзаке"}}],
+		"quests": [{"name": "найти записку", "steps": [{"text": "найти записку", "done": {"has_items": ["записка"]}}]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands, err := world.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(commands, ", "); got != "надеть рюкзак, осмотреть стол, взять записка" {
+		t.Errorf("unexpected solution: %s", got)
+	}
+}
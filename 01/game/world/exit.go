@@ -0,0 +1,136 @@
+package world
+
+import "slices"
+
+// Exit - проход из комнаты. Обратный проход описывается в соседней комнате отдельно,
+// так что у каждого направления могут быть свои условия
+type Exit struct {
+	// название направления для команды идти, по умолчанию название комнаты
+	Name string
+	To   *Room
+	// дверь может быть общей у прохода туда и обратно
+	Door *Door
+	// проход только в одну сторону, обратного прохода нет
+	OneWay bool
+	If     Condition
+	// ответ, если условие прохода не выполнено
+	Refusal string
+}
+
+type Door struct {
+	// по ID на дверь ссылаются проходы и условия
+	ID string
+	// по названию дверь ищется как цель для применения предметов и команд открыть/закрыть
+	Name     string
+	IsClosed bool
+	// запертую дверь можно открыть только ключом Key. Запирается она снова, только если
+	// её закрывают ключом или игрок, у которого ключ с собой, иначе остаётся незапертой
+	Locked bool
+	Key    Item
+	States map[bool]string
+	// что игрок видит, осматривая дверь
+	Description string
+}
+
+// Toggle открывает закрытую дверь и закрывает открытую, возвращает её новое состояние.
+// lock - закрываемая дверь запирается, если у неё есть ключ
+func (door *Door) Toggle(lock bool) string {
+	door.IsClosed = !door.IsClosed
+	door.Locked = door.IsClosed && lock && door.Key != ""
+	return door.States[door.IsClosed]
+}
+
+// unlocks - можно ли отпереть дверь этим предметом. Незапертую дверь открывает что угодно
+func (door *Door) unlocks(item Item) bool {
+	return !door.Locked || door.Key == item
+}
+
+func (room *Room) findExit(name string) *Exit {
+	for _, exit := range room.Exits {
+		if exit.Name == name {
+			return exit
+		}
+	}
+	return nil
+}
+
+func (room *Room) leadsTo(to *Room) bool {
+	for _, exit := range room.Exits {
+		if exit.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (room *Room) findDoor(name string) *Door {
+	for _, exit := range room.Exits {
+		if exit.Door != nil && exit.Door.Name == name {
+			return exit.Door
+		}
+	}
+	return nil
+}
+
+func (world *World) Door(id string) *Door {
+	for _, door := range world.Doors {
+		if door.ID == id {
+			return door
+		}
+	}
+	return nil
+}
+
+// Go переводит игрока по проходу с указанным названием
+func (player *Player) Go(name string) string {
+	exit := player.CurrentRoom.findExit(name)
+	if exit == nil {
+		return player.T("нет пути в %s", Name(name))
+	}
+	if exit.Door != nil && exit.Door.IsClosed {
+		return player.T(exit.Door.States[true])
+	}
+	if !exit.If.Check(player) {
+		return player.T(exit.Refusal)
+	}
+	from := player.CurrentRoom
+	if answer, blocked := player.blockedBy(from, OnLeave, "", exit.Name); blocked {
+		return answer
+	}
+	if answer, blocked := player.blockedBy(exit.To, OnEnter, "", ""); blocked {
+		return answer
+	}
+	player.Dialogue = nil
+	player.publish("ушёл в %s", Name(exit.Name))
+	player.CurrentRoom = exit.To
+	player.Visited[exit.To.Name] = true
+	player.publish("пришёл")
+	// обработчики срабатывают до ответа, чтобы он показал уже изменённую комнату
+	says := append(player.runHooks(from, OnLeave, "", exit.Name), player.runHooks(exit.To, OnEnter, "", "")...)
+	result := withHooks(player.T(player.CurrentRoom.Note)+". "+player.CurrentRoom.NextRoomsList(player), says)
+	for _, strike := range player.ambush() {
+		result += "\n" + strike
+	}
+	return result
+}
+
+func (player *Player) openDoor(door *Door) string {
+	if !door.IsClosed {
+		return player.T("%s уже открыта", Name(door.Name))
+	}
+	if door.Locked && !slices.Contains(player.Inventory, door.Key) {
+		return player.T("%s заперта", Name(door.Name))
+	}
+	result := player.T(door.Toggle(false))
+	player.publish("открыл %s", Name(door.Name))
+	return result
+}
+
+func (player *Player) closeDoor(door *Door) string {
+	if door.IsClosed {
+		return player.T("%s уже закрыта", Name(door.Name))
+	}
+	result := player.T(door.Toggle(slices.Contains(player.Inventory, door.Key)))
+	player.publish("закрыл %s", Name(door.Name))
+	return result
+}
@@ -0,0 +1,77 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+const exitsWorld = `{
+	"start": "холл",
+	"rooms": [
+		{"name": "холл", "note": "холл", "storages": [{"name_in_case": "у входа", "items": ["рюкзак", "ключ", "отмычка"]}],
+			"exits": [
+				{"to": "кладовка", "door": "кладовая"},
+				{"to": "двор", "door": "входная", "if": {"wears": ["рюкзак"]}, "refusal": "без рюкзака не выйти"}
+			]},
+		{"name": "кладовка", "note": "кладовка", "exits": [{"to": "холл", "door": "кладовая"}]},
+		{"name": "двор", "note": "двор", "exits": [{"to": "подвал", "one_way": true}, {"name": "домой", "to": "холл", "door": "входная"}]},
+		{"name": "подвал", "note": "подвал"}
+	],
+	"doors": [
+		{"id": "входная", "closed_note": "закрыто", "open_note": "открыто"},
+		{"id": "кладовая", "name": "дверца", "closed": true, "locked": true, "key": "ключ",
+			"closed_note": "дверца закрыта", "open_note": "дверца открыта"}
+	],
+	"items": [
+		{"name": "рюкзак", "container": {"name_in_case": "в рюкзаке"}},
+		{"name": "ключ", "properties": {"ключ": "дверь"}},
+		{"name": "отмычка", "properties": {"ключ": "дверь"}}
+	],
+	"interactions": [{"item_property": "ключ", "target": "дверца", "toggle_door": true}]
+}`
+
+func TestExits(t *testing.T) {
+	world, err := Load(strings.NewReader(exitsWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.Go("кладовка") }, "дверца закрыта"},
+		{func() string { return player.OpenStorage("дверца") }, "дверца заперта"},
+		{func() string { return player.Go("двор") }, "без рюкзака не выйти"},
+		{func() string { return player.WearItem("рюкзак") }, "вы надели: рюкзак"},
+		{func() string { return player.TakeItem("отмычка") }, "предмет добавлен в инвентарь: отмычка"},
+		{func() string { return player.UseItem("отмычка", "дверца") }, "не к чему применить"},
+		{func() string { return player.TakeItem("ключ") }, "предмет добавлен в инвентарь: ключ"},
+		{func() string { return player.UseItem("ключ", "дверца") }, "дверца открыта"},
+		// закрытая отмычкой дверь не запирается, запирает только ключ
+		{func() string { return player.UseItem("отмычка", "дверца") }, "дверца закрыта"},
+		{func() string { return player.UseItem("отмычка", "дверца") }, "дверца открыта"},
+		{func() string { return player.UseItem("ключ", "дверца") }, "дверца закрыта"},
+		{func() string { return player.UseItem("отмычка", "дверца") }, "не к чему применить"},
+		{func() string { return player.OpenStorage("дверца") }, "дверца открыта"},
+		{func() string { return player.Go("кладовка") }, "кладовка. можно пройти - холл"},
+		{func() string { return player.CloseStorage("дверца") }, "дверца закрыта"},
+		{func() string { return player.OpenStorage("дверца") }, "дверца открыта"},
+		// без ключа дверь закрывается, но не запирается
+		{func() string { return player.DropItem("ключ") }, "вы выбросили: ключ"},
+		{func() string { return player.CloseStorage("дверца") }, "дверца закрыта"},
+		{func() string { return player.OpenStorage("дверца") }, "дверца открыта"},
+		{func() string { return player.Go("холл") }, "холл. можно пройти - кладовка, двор"},
+		{func() string { return player.Go("двор") }, "двор. можно пройти - подвал, домой"},
+		{func() string { return player.Go("подвал") }, "подвал. можно пройти - "},
+		{func() string { return player.Go("двор") }, "нет пути в двор"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if door := world.Door("кладовая"); door.IsClosed || door.Locked {
+		t.Errorf("door must stay open")
+	}
+}
@@ -0,0 +1,164 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+// GenerateOptions - параметры генератора подземелий
+type GenerateOptions struct {
+	// один и тот же seed даёт одно и то же подземелье
+	Seed  int64
+	Rooms int
+	// сколько дверей запереть, ключ от каждой лежит там, куда можно попасть раньше неё
+	LockedDoors int
+	// сколько лишних предметов разложить по комнатам
+	Items int
+}
+
+var (
+	dungeonRooms = []string{"зал", "кладовая", "галерея", "склеп", "часовня", "библиотека", "оружейная",
+		"темница", "погреб", "башня", "спальня", "казарма", "архив", "мастерская", "трапезная", "сокровищница"}
+	dungeonStorages = []string{"в сундуке", "на полке", "в нише", "на столе", "в бочке"}
+	dungeonItems    = []string{"свеча", "монета", "кость", "верёвка", "фляга", "карта", "кинжал", "амулет"}
+)
+
+// dungeonName возвращает i-е название из списка, а когда список кончается, добавляет номер
+func dungeonName(names []string, i int) string {
+	name := names[i%len(names)]
+	if i >= len(names) {
+		name += strconv.Itoa(i/len(names) + 1)
+	}
+	return name
+}
+
+func (options GenerateOptions) build() (*worldFile, *World, error) {
+	data, err := options.generate()
+	if err != nil {
+		return nil, nil, err
+	}
+	world, err := data.build()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = world.CheckSolvable(data.Quests[0].Steps[0].Done.InRoom); err != nil {
+		return nil, nil, err
+	}
+	return data, world, nil
+}
+
+// generate строит описание мира: комнаты соединены деревом проходов, так что все они достижимы,
+// цель - самая дальняя от старта комната
+func (options GenerateOptions) generate() (*worldFile, error) {
+	if options.Rooms < 2 {
+		return nil, fmt.Errorf("dungeon needs at least 2 rooms")
+	}
+	if options.LockedDoors < 0 || options.LockedDoors > options.Rooms-1 {
+		return nil, fmt.Errorf("dungeon with %d rooms can have from 0 to %d locked doors", options.Rooms, options.Rooms-1)
+	}
+	random := rand.New(rand.NewSource(options.Seed))
+	data := &worldFile{Rooms: make([]roomFile, options.Rooms)}
+	order := random.Perm(len(dungeonRooms) * (options.Rooms/len(dungeonRooms) + 1))
+	parent := make([]int, options.Rooms)
+	depth := make([]int, options.Rooms)
+	goal := 0
+	for i := range data.Rooms {
+		name := dungeonName(dungeonRooms, order[i])
+		data.Rooms[i] = roomFile{
+			Name:     name,
+			Note:     name,
+			Storages: []*Storage{{NameInCase: dungeonStorages[random.Intn(len(dungeonStorages))], Items: []Item{}}},
+		}
+		if i == 0 {
+			continue
+		}
+		parent[i] = random.Intn(i)
+		depth[i] = depth[parent[i]] + 1
+		if depth[i] > depth[goal] {
+			goal = i
+		}
+	}
+	data.Start = data.Rooms[0].Name
+	data.Rooms[0].Storages[0].Items = []Item{"рюкзак"}
+	data.Items = append(data.Items, &ItemType{Name: "рюкзак", Container: &Storage{NameInCase: "в рюкзаке"}})
+
+	// запираются случайные проходы дерева, дверь с номером k стоит перед комнатой-потомком
+	locked := make(map[int]int, options.LockedDoors)
+	for _, child := range random.Perm(options.Rooms - 1)[:options.LockedDoors] {
+		locked[child+1] = len(locked) + 1
+	}
+	for child := 1; child < options.Rooms; child++ {
+		door := ""
+		if k, ok := locked[child]; ok {
+			door = doorID(k)
+		}
+		room, up := &data.Rooms[child], &data.Rooms[parent[child]]
+		up.Exits = append(up.Exits, exitFile{To: room.Name, Door: door})
+		room.Exits = append(room.Exits, exitFile{To: up.Name, Door: door})
+	}
+	// ключ от k-й двери кладётся в комнату, куда можно дойти через уже открытые двери 1..k-1:
+	// тогда двери открываются по порядку
+	for k := 1; k <= options.LockedDoors; k++ {
+		door := doorID(k)
+		key := Item("ключ" + strconv.Itoa(k))
+		var reachable []int
+		for i := range data.Rooms {
+			if reachableBefore(parent, locked, i, k) {
+				reachable = append(reachable, i)
+			}
+		}
+		room := &data.Rooms[reachable[random.Intn(len(reachable))]]
+		room.Storages[0].Items = append(room.Storages[0].Items, key)
+		data.Doors = append(data.Doors, doorFile{
+			ID: door, Name: door, Closed: true, Locked: true, Key: key,
+			Close: door + " заперта", Open: door + " открыта",
+		})
+		data.Items = append(data.Items, &ItemType{Name: key, Weight: 1, Volume: 1})
+		data.Interactions = append(data.Interactions, &Interaction{Item: key, Target: door, ToggleDoor: true})
+	}
+	for i := 0; i < options.Items; i++ {
+		room := &data.Rooms[random.Intn(options.Rooms)]
+		room.Storages[0].Items = append(room.Storages[0].Items, Item(dungeonItems[random.Intn(len(dungeonItems))]))
+	}
+	data.Quests = []*Quest{{
+		Name:   "выбраться",
+		Room:   data.Start,
+		Steps:  []*Objective{{Text: "найти " + data.Rooms[goal].Name, Done: Condition{InRoom: data.Rooms[goal].Name}}},
+		Reward: Reward{Text: "подземелье пройдено"},
+	}}
+	return data, nil
+}
+
+func doorID(k int) string {
+	return "дверь" + strconv.Itoa(k)
+}
+
+// reachableBefore - можно ли дойти до комнаты i от старта, если открыты только двери с номерами меньше k
+func reachableBefore(parent []int, locked map[int]int, i, k int) bool {
+	for ; i != 0; i = parent[i] {
+		if door, ok := locked[i]; ok && door >= k {
+			return false
+		}
+	}
+	return true
+}
+
+// Generate строит подземелье и сразу проверяет, что его можно пройти
+func Generate(options GenerateOptions) (*World, error) {
+	_, world, err := options.build()
+	return world, err
+}
+
+// GenerateFile записывает проверенное подземелье в формате файла мира
+func GenerateFile(options GenerateOptions, w io.Writer) error {
+	data, _, err := options.build()
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
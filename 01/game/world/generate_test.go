@@ -0,0 +1,61 @@
+package world
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	options := GenerateOptions{Rooms: 12, LockedDoors: 5, Items: 6}
+	for seed := int64(0); seed < 50; seed++ {
+		options.Seed = seed
+		world, err := Generate(options)
+		if err != nil {
+			t.Fatalf("seed %d: %s", seed, err)
+		}
+		if len(world.Rooms) != options.Rooms || len(world.Doors) != options.LockedDoors {
+			t.Fatalf("seed %d: %d rooms and %d doors", seed, len(world.Rooms), len(world.Doors))
+		}
+	}
+
+	var first, second bytes.Buffer
+	options.Seed = 7
+	if err := GenerateFile(options, &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateFile(options, &second); err != nil {
+		t.Fatal(err)
+	}
+	if first.String() != second.String() {
+		t.Error("same seed gave different dungeons")
+	}
+	if _, err := Load(&first); err != nil {
+		t.Errorf("generated file does not load: %s", err)
+	}
+	if _, err := Generate(GenerateOptions{Rooms: 3, LockedDoors: 3}); err == nil {
+		t.Error("expected error for too many locked doors")
+	}
+}
+
+func TestCheckSolvable(t *testing.T) {
+	// ключ от двери лежит за ней самой
+	world, err := Load(strings.NewReader(`{
+		"start": "холл",
+		"rooms": [
+			{"name": "холл", "exits": [{"to": "сейф", "door": "стальная"}]},
+			{"name": "сейф", "storages": [{"name_in_case": "на полке", "items": ["ключ"]}],
+				"exits": [{"to": "холл", "door": "стальная"}]}
+		],
+		"doors": [{"id": "стальная", "closed": true, "locked": true, "key": "ключ"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = world.CheckSolvable("сейф"); err == nil {
+		t.Error("expected unreachable goal")
+	}
+	if err = world.CheckSolvable("холл"); err != nil {
+		t.Error(err)
+	}
+}
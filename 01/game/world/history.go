@@ -0,0 +1,127 @@
+package world
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// HistoryLimit - сколько последних действий игрока можно отменить
+const HistoryLimit = 50
+
+// Action - команда, изменившая мир, с состояниями до и после неё
+type Action struct {
+	Command string
+	Answer  string
+	before  *State
+	after   *State
+}
+
+// History - журнал действий игрока. Первые Done действий применены,
+// остальные отменены и их можно повторить
+type History struct {
+	Actions []*Action
+	Done    int
+	// номер изменения мира после последнего действия игрока. Если мир с тех пор менял
+	// кто-то ещё, отмена затёрла бы чужие изменения, поэтому она запрещена
+	revision int
+	// игрок погиб, и журнал надо очистить вместо записи действия
+	forget bool
+}
+
+// Record добавляет команду в журнал, если она что-то изменила. before - состояние до команды
+func (player *Player) Record(command, answer string, before *State) {
+	if player.History.forget {
+		player.ForgetHistory()
+		return
+	}
+	after := player.World.SaveState(player)
+	if sameState(before, after) {
+		return
+	}
+	history := &player.History
+	// после нового действия отменённые повторить уже нельзя
+	history.Actions = append(history.Actions[:history.Done], &Action{
+		Command: command,
+		Answer:  answer,
+		before:  before,
+		after:   after,
+	})
+	if len(history.Actions) > HistoryLimit {
+		history.Actions = history.Actions[len(history.Actions)-HistoryLimit:]
+	}
+	history.Done = len(history.Actions)
+	player.World.revision++
+	history.revision = player.World.revision
+}
+
+// sameState сравнивает состояния без учёта времени: само течение времени действием не считается
+func sameState(before, after *State) bool {
+	withTime := *after
+	withTime.Minute = before.Minute
+	return reflect.DeepEqual(before, &withTime)
+}
+
+func (player *Player) Undo() string {
+	history := &player.History
+	if history.Done == 0 {
+		return player.T("нечего отменять")
+	}
+	action := history.Actions[history.Done-1]
+	if !player.restore(action.before) {
+		return player.T("нельзя отменить: мир изменился")
+	}
+	history.Done--
+	return player.T("отменено: %s", action.Command)
+}
+
+func (player *Player) Redo() string {
+	history := &player.History
+	if history.Done == len(history.Actions) {
+		return player.T("нечего повторять")
+	}
+	action := history.Actions[history.Done]
+	if !player.restore(action.after) {
+		return player.T("нельзя повторить: мир изменился")
+	}
+	history.Done++
+	return player.T("повторено: %s", action.Command)
+}
+
+// restore возвращает мир к состоянию из журнала. Часы общие и назад не идут,
+// иначе события по времени сработали бы ещё раз
+func (player *Player) restore(state *State) bool {
+	if player.History.revision != player.World.revision {
+		return false
+	}
+	withClock := *state
+	withClock.Minute = player.World.Clock.Minute
+	if err := player.World.RestoreState(player, &withClock); err != nil {
+		return false
+	}
+	player.World.revision++
+	player.History.revision = player.World.revision
+	return true
+}
+
+// ForgetHistory очищает журнал, когда мир изменился не командой, например при загрузке игры
+func (player *Player) ForgetHistory() {
+	player.History = History{}
+	player.World.revision++
+}
+
+// HistoryList - журнал действий, отменённые помечены
+func (player *Player) HistoryList() string {
+	if len(player.History.Actions) == 0 {
+		return player.T("журнал пуст")
+	}
+	lines := make([]string, 0, len(player.History.Actions))
+	for i, action := range player.History.Actions {
+		line := fmt.Sprintf("%d. %s: %s", i+1, action.Command, action.Answer)
+		if i >= player.History.Done {
+			line += player.T(" (отменено)")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,88 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHistoryConflict(t *testing.T) {
+	world, err := Load(strings.NewReader(questWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := world.NewPlayer(), world.NewPlayer()
+	act := func(player *Player, command string, action func() string) {
+		before := world.SaveState(player)
+		player.Record(command, action(), before)
+	}
+
+	act(first, "надеть сумку", func() string { return first.WearItem("сумка") })
+	act(first, "осмотреться", first.LookAround)
+	if len(first.History.Actions) != 1 {
+		t.Fatalf("look around must not be recorded: %s", first.HistoryList())
+	}
+	act(second, "открыть дверь", func() string { return second.OpenStorage("дверь") })
+	if answer := first.Undo(); answer != "нельзя отменить: мир изменился" {
+		t.Errorf("undo over another player's action: %s", answer)
+	}
+	if answer := second.Undo(); answer != "отменено: открыть дверь" {
+		t.Errorf("unexpected undo: %s", answer)
+	}
+	if !world.Door("входная").IsClosed {
+		t.Errorf("undo did not close the door")
+	}
+	// отмена второго игрока тоже изменила мир
+	if answer := first.Undo(); answer != "нельзя отменить: мир изменился" {
+		t.Errorf("undo after another player's undo: %s", answer)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	world, err := Load(strings.NewReader(questWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	for i := 0; i < HistoryLimit+5; i++ {
+		before := world.SaveState(player)
+		world.Door("входная").Toggle(true)
+		player.Record("открыть дверь", "", before)
+	}
+	if len(player.History.Actions) != HistoryLimit || player.History.Done != HistoryLimit {
+		t.Errorf("history not bounded: %d actions, %d done", len(player.History.Actions), player.History.Done)
+	}
+}
+
+func TestHistoryClock(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "кухня",
+		"rooms": [{"name": "кухня", "note": "кухня", "storages": [{"name_in_case": "на столе", "items": ["чай"]}],
+			"events": [{"after": 2, "set_note": "чай остыл"}]}],
+		"items": [{"name": "сумка", "container": {"name_in_case": "в сумке"}}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Equipment[DefaultSlot] = "сумка"
+	before := world.SaveState(player)
+	player.Record("взять чай", player.TakeItem("чай"), before)
+	world.Tick(1)
+	if answer := player.Undo(); answer != "отменено: взять чай" {
+		t.Fatalf("unexpected undo: %s", answer)
+	}
+	if world.Clock.Minute != 1 {
+		t.Errorf("undo moved the clock to %d", world.Clock.Minute)
+	}
+	if answer := player.Redo(); answer != "повторено: взять чай" {
+		t.Fatalf("unexpected redo: %s", answer)
+	}
+	// сработавшее событие меняет мир, и отмена его бы откатила
+	world.Tick(1)
+	if world.Rooms["кухня"].Note != "чай остыл" {
+		t.Fatal("event did not fire")
+	}
+	if answer := player.Undo(); answer != "нельзя отменить: мир изменился" {
+		t.Errorf("undo over a timed event: %s", answer)
+	}
+}
@@ -0,0 +1,105 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Действия игрока, на которые может отвечать комната
+const (
+	OnEnter = "enter"
+	OnLeave = "leave"
+	OnLook  = "look"
+	OnTake  = "take"
+	OnUse   = "use"
+)
+
+var hookActions = []string{OnEnter, OnLeave, OnLook, OnTake, OnUse}
+
+// Hook - реакция комнаты на действие игрока. Обработчик с Block запрещает действие
+// и отвечает вместо него, остальные срабатывают после действия и дописывают Say к ответу.
+// Обработчики проверяются в порядке описания
+type Hook struct {
+	On string `json:"on"`
+	// для take и use - какой предмет, для use ещё и цель, для leave - название прохода;
+	// пусто - любой
+	Item   Item      `json:"item,omitempty"`
+	Target string    `json:"target,omitempty"`
+	If     Condition `json:"if"`
+	// срабатывает один раз за игру
+	Once    bool   `json:"once,omitempty"`
+	Block   string `json:"block,omitempty"`
+	Say     string `json:"say,omitempty"`
+	SetFlag string `json:"set_flag,omitempty"`
+	Effect
+
+	fired bool
+}
+
+func (hook *Hook) matches(player *Player, on string, item Item, target string) bool {
+	if hook.On != on || hook.Once && hook.fired {
+		return false
+	}
+	if hook.Item != "" && hook.Item != item || hook.Target != "" && hook.Target != target {
+		return false
+	}
+	return hook.If.Check(player)
+}
+
+func (hook *Hook) fire(player *Player, room *Room) {
+	hook.fired = true
+	if hook.SetFlag != "" {
+		player.Flags[hook.SetFlag] = true
+	}
+	hook.apply(player.World, room)
+}
+
+// blockedBy возвращает ответ первого запрещающего обработчика, если такой сработал
+func (player *Player) blockedBy(room *Room, on string, item Item, target string) (string, bool) {
+	for _, hook := range room.Hooks {
+		if hook.Block != "" && hook.matches(player, on, item, target) {
+			hook.fire(player, room)
+			return player.T(hook.Block), true
+		}
+	}
+	return "", false
+}
+
+// runHooks запускает обработчики уже выполненного действия и возвращает их реплики
+func (player *Player) runHooks(room *Room, on string, item Item, target string) []string {
+	var says []string
+	for _, hook := range room.Hooks {
+		if hook.Block != "" || !hook.matches(player, on, item, target) {
+			continue
+		}
+		hook.fire(player, room)
+		if hook.Say != "" {
+			says = append(says, player.T(hook.Say))
+		}
+	}
+	return says
+}
+
+// withHooks дописывает реплики обработчиков к ответу, каждую с новой строки
+func withHooks(answer string, says []string) string {
+	for _, say := range says {
+		answer += "\n" + say
+	}
+	return answer
+}
+
+func (world *World) checkHook(hook *Hook) error {
+	if !slices.Contains(hookActions, hook.On) {
+		return fmt.Errorf("unknown action %q", hook.On)
+	}
+	if hook.Item != "" && hook.On != OnTake && hook.On != OnUse {
+		return fmt.Errorf("item filter on %s", hook.On)
+	}
+	if hook.Target != "" && hook.On != OnUse && hook.On != OnLeave {
+		return fmt.Errorf("target filter on %s", hook.On)
+	}
+	if err := world.checkCondition(hook.If); err != nil {
+		return err
+	}
+	return world.checkEffect(hook.Effect)
+}
@@ -0,0 +1,84 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+const hooksWorld = `{
+	"start": "холл",
+	"rooms": [
+		{"name": "холл", "note": "холл", "look_around_note": "пыльный холл",
+			"storages": [{"name_in_case": "на полке", "items": ["рюкзак", "ваза", "ключ", "пропуск"]}],
+			"exits": [{"to": "двор", "door": "входная"}, {"to": "склад"}],
+			"hooks": [
+				{"on": "look", "once": true, "say": "где-то скрипнула половица", "set_look_around_note": "тихий холл"},
+				{"on": "take", "item": "ваза", "block": "ваза приклеена к полке"},
+				{"on": "take", "item": "пропуск", "say": "охранник кивнул", "set_flag": "пропуск"},
+				{"on": "leave", "target": "двор", "say": "дверь захлопнулась за спиной", "lock_door": "входная"}
+			]},
+		{"name": "двор", "note": "двор", "exits": [{"name": "домой", "to": "холл", "door": "входная"}],
+			"hooks": [{"on": "use", "item": "ключ", "target": "дверь", "say": "замок заскрипел"}]},
+		{"name": "склад", "note": "склад", "exits": [{"to": "холл"}],
+			"hooks": [{"on": "enter", "if": {"never": true}, "block": "не пускают"},
+				{"on": "enter", "say": "охранник смотрит на пропуск"}]}
+	],
+	"doors": [{"id": "входная", "closed_note": "дверь закрыта", "open_note": "дверь открыта"}],
+	"items": [{"name": "рюкзак", "container": {"name_in_case": "в рюкзаке"}}],
+	"interactions": [{"item": "ключ", "target": "дверь", "toggle_door": true}]
+}`
+
+func TestHooks(t *testing.T) {
+	world, err := Load(strings.NewReader(hooksWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{player.LookAround, "тихий холл, на полке: рюкзак, ваза, ключ, пропуск. можно пройти - двор, склад\nгде-то скрипнула половица"},
+		{player.LookAround, "тихий холл, на полке: рюкзак, ваза, ключ, пропуск. можно пройти - двор, склад"},
+		{func() string { return player.WearItem("рюкзак") }, "вы надели: рюкзак"},
+		{func() string { return player.TakeItem("ваза") }, "ваза приклеена к полке"},
+		{func() string { return player.TakeItem("ключ") }, "предмет добавлен в инвентарь: ключ"},
+		{func() string { return player.TakeItem("пропуск") }, "предмет добавлен в инвентарь: пропуск\nохранник кивнул"},
+		{func() string { return player.Go("склад") }, "склад. можно пройти - холл\nохранник смотрит на пропуск"},
+		{func() string { return player.Go("холл") }, "холл. можно пройти - двор, склад"},
+		{func() string { return player.Go("двор") }, "двор. можно пройти - домой\nдверь захлопнулась за спиной"},
+		{func() string { return player.Go("домой") }, "дверь закрыта"},
+		{func() string { return player.UseItem("ключ", "дверь") }, "дверь открыта\nзамок заскрипел"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if !player.Flags["пропуск"] {
+		t.Error("hook did not set flag")
+	}
+
+	// одноразовый обработчик остаётся сработавшим после загрузки
+	state := world.SaveState(player)
+	fresh, _ := Load(strings.NewReader(hooksWorld))
+	other := fresh.NewPlayer()
+	if err := fresh.RestoreState(other, state); err != nil {
+		t.Fatal(err)
+	}
+	other.Go("домой")
+	if answer := other.LookAround(); strings.Contains(answer, "половица") {
+		t.Errorf("once hook fired again: %s", answer)
+	}
+
+	for _, hooks := range []string{
+		`[{"on": "sneeze"}]`,
+		`[{"on": "look", "item": "ваза"}]`,
+		`[{"on": "enter", "lock_door": "нет"}]`,
+		`[{"on": "enter", "if": {"quest_done": "нет"}}]`,
+	} {
+		if _, err := Load(strings.NewReader(`{"start": "a", "rooms": [{"name": "a", "hooks": ` + hooks + `}]}`)); err == nil {
+			t.Errorf("expected error for hooks %s", hooks)
+		}
+	}
+}
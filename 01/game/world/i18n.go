@@ -0,0 +1,259 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Locale - язык ответов игроку. Код и описания миров пишутся по-русски,
+// другие языки переводят готовые русские тексты
+type Locale string
+
+const (
+	Russian Locale = "ru"
+	English Locale = "en"
+)
+
+var Locales = []Locale{Russian, English}
+
+// catalog - переводы сообщений игры. Ключ - русский шаблон сообщения в том виде,
+// в каком он написан в коде, поэтому русские ответы от каталога не зависят
+var catalog = map[Locale]map[string]string{
+	English: {
+		// осмотр и перемещение
+		"можно пройти - %s":      "you can go to - %s",
+		"надо %s":                "you need to %s",
+		" и ":                    " and ",
+		"пустая комната":         "empty room",
+		"%s закрыт":              "%s is closed",
+		"здесь: %s":              "here: %s",
+		"нет пути в %s":          "there is no way to %s",
+		"туда нельзя":            "you can't go there",
+		"на полу":                "on the floor",
+		"дверь":                  "door",
+		"день %d, %02d:%02d, %s": "day %d, %02d:%02d, %s",
+		"весна":                  "spring",
+		"лето":                   "summer",
+		"осень":                  "autumn",
+		"зима":                   "winter",
+		"%s уже открыта":         "%s is already open",
+		"%s заперта":             "%s is locked",
+		"%s уже закрыта":         "%s is already closed",
+		"нет такого":             "there is no such thing",
+		"ничего особенного":      "nothing special",
+		"уточните, где: %s":      "specify where: %s",
+		"вы нашли: %s":           "you found: %s",
+		"нельзя открыть %s":      "%s can't be opened",
+		"нельзя закрыть %s":      "%s can't be closed",
+		"%s уже открыт":          "%s is already open",
+		"%s уже закрыт":          "%s is already closed",
+		"%s заперт":              "%s is locked",
+		"%s открыт":              "%s is open",
+		"нет предмета в инвентаре - %s": "no such item in the inventory - %s",
+		// предметы и инвентарь
+		"предмет добавлен в инвентарь: %s": "item added to the inventory: %s",
+		"вы надели: %s":                  "you put on: %s",
+		"нельзя надеть %s":               "%s can't be worn",
+		"сначала снимите %s":             "take off %s first",
+		"на вас нет %s":                  "you don't wear %s",
+		"вы сняли: %s":                   "you took off: %s",
+		"вы сняли и положили на пол: %s": "you took off and put on the floor: %s",
+		"на вас ничего не надето":        "you wear nothing",
+		"надето: %s":                     "wearing: %s",
+		"тепло %d":                       "warmth %d",
+		"есть свет":                      "you have light",
+		"спина":                          "back",
+		"голова":                         "head",
+		"руки":                           "hands",
+		"не к чему применить":            "nothing to use it on",
+		"нельзя положить предмет в самого себя": "an item can't be put into itself",
+		"вы положили %s в %s":                   "you put %s into %s",
+		"некуда класть":                         "nowhere to put it",
+		"%s нет места":                          "no room left %s",
+		"слишком тяжело":                        "too heavy",
+		"не влезает":                            "doesn't fit",
+		"инвентарь пуст":                        "the inventory is empty",
+		"в инвентаре: %s":                       "in the inventory: %s",
+		"предметов %s":                          "items %s",
+		"вес %s":                                "weight %s",
+		"объём %s":                              "volume %s",
+		"вы выбросили: %s":                      "you dropped: %s",
+		"не поместилось и осталось на полу: %s": "didn't fit and stayed on the floor: %s",
+		"вы сказали: %s": "you said: %s",
+		// задания и разговоры
+		"задание выполнено: %s":           "quest completed: %s",
+		"награда: %s":                     "reward: %s",
+		"%s: выполнено":                   "%s: completed",
+		"заданий нет":                     "no quests",
+		"здесь нет %s":                    "%s is not here",
+		"%s не хочет разговаривать":       "%s doesn't want to talk",
+		"вы ни с кем не разговариваете":   "you are not talking to anyone",
+		"нет такого варианта ответа":      "there is no such answer",
+		"разговор окончен":                "the conversation is over",
+		"не хватает аргументов: %s":       "not enough arguments: %s",
+		"слишком много аргументов: %s":    "too many arguments: %s",
+		"нечего отменять":                 "nothing to undo",
+		"нельзя отменить: мир изменился":  "can't undo: the world has changed",
+		"отменено: %s":                    "undone: %s",
+		"нечего повторять":                "nothing to redo",
+		"нельзя повторить: мир изменился": "can't redo: the world has changed",
+		"повторено: %s":                   "redone: %s",
+		"журнал пуст":                     "the log is empty",
+		" (отменено)":                     " (undone)",
+		// драки
+		"здоровье %d/%d":                     "health %d/%d",
+		"с %s драться нельзя":                "you can't fight %s",
+		"вы ударили %s: -%d. %s":             "you hit %s: -%d. %s",
+		"вы промахнулись":                    "you missed",
+		"вы ударили %s: -%d, осталось %d/%d": "you hit %s: -%d, %d/%d left",
+		"%s повержен":                        "%s is defeated",
+		"выпало: %s":                         "dropped: %s",
+		"%s ударил вас: -%d, %s":             "%s hit you: -%d, %s",
+		"%s промахнулся":                     "%s missed",
+		"вы погибли и очнулись: %s":          "you died and woke up: %s",
+		"%s нельзя съесть":                   "%s can't be eaten",
+		"вы подкрепились: %s. %s":            "you refreshed yourself: %s. %s",
+		"%s, сила %d, защита %d":             "%s, strength %d, armor %d",
+		// события других игроков, первым идёт имя игрока
+		"%s взял %s":                    "%s took %s",
+		"%s взял %s из %s":              "%s took %s from %s",
+		"%s нашёл %s в %s":              "%s found %s in %s",
+		"%s надел %s":                   "%s put on %s",
+		"%s снял %s":                    "%s took off %s",
+		"%s применил %s: %s":            "%s used %s: %s",
+		"%s говорит: %s":                "%s says: %s",
+		"%s ушёл в %s":                  "%s went to %s",
+		"%s пришёл":                     "%s came in",
+		"%s ушёл из игры":               "%s left the game",
+		"%s открыл %s":                  "%s opened %s",
+		"%s закрыл %s":                  "%s closed %s",
+		"%s положил %s в %s":            "%s put %s into %s",
+		"%s выбросил %s":                "%s dropped %s",
+		"%s разговаривает: %s":          "%s talks to %s",
+		"%s атаковал %s":                "%s attacked %s",
+		"%s победил %s":                 "%s defeated %s",
+		"%s погиб":                      "%s died",
+		"%s подкрепился: %s":            "%s refreshed with %s",
+		"язык: %s":                      "language: %s",
+		"нет такого языка: %s":          "no such language: %s",
+		"формат: %s":                    "format: %s",
+		"нет такого формата: %s":        "no such format: %s",
+		"неизвестная команда":           "unknown command",
+		"возможно, вы имели в виду: %s": "maybe you meant: %s",
+		"игра сохранена: %s":            "game saved: %s",
+		"слишком много сохранений, перезапишите одно из старых": "too many saves, overwrite one of the old ones",
+		"не удалось сохранить игру":                             "failed to save the game",
+		"игра загружена: %s":                                    "game loaded: %s",
+		"не удалось загрузить игру":                             "failed to load the game",
+		"в общем мире загружать игру нельзя":                    "a game can't be loaded into a shared world",
+		// помощь: аргументы и описания команд
+		"комната":          "room",
+		"предмет":          "item",
+		"хранилище":        "container",
+		"цель":             "target",
+		"собеседник":       "character",
+		"номер":            "number",
+		"слот":             "slot",
+		"язык":             "language",
+		"формат":           "format",
+		"описание комнаты": "describe the room",
+		"подробно осмотреть комнату, предмет, хранилище или дверь, можно уточнить, где лежит предмет": "examine the room, an item, a container or a door in detail, optionally saying where the item lies",
+		"пройти в соседнюю комнату":                                           "go to a neighbouring room",
+		"положить предмет в инвентарь, можно достать из хранилища":            "put an item into the inventory, optionally from a container",
+		"переложить предмет из инвентаря в хранилище":                         "move an item from the inventory into a container",
+		"выбросить предмет из инвентаря на пол":                               "drop an item from the inventory on the floor",
+		"что у вас с собой":                                                   "what you carry",
+		"список заданий":                                                      "list quests",
+		"открыть шкаф, сейф, контейнер или дверь":                             "open a cupboard, safe, container or door",
+		"закрыть шкаф, сейф, контейнер или дверь":                             "close a cupboard, safe, container or door",
+		"надеть предмет":                                                      "put on an item",
+		"применить предмет из инвентаря":                                      "use an item from the inventory",
+		"напасть на персонажа, можно ударить предметом из инвентаря":          "attack a character, optionally with an item from the inventory",
+		"съесть или выпить предмет из инвентаря, чтобы восстановить здоровье": "eat or drink an item from the inventory to restore health",
+		"здоровье и характеристики":                                           "health and stats",
+		"сказать что-то игрокам в комнате":                                    "say something to the players in the room",
+		"начать разговор с персонажем":                                        "start a conversation with a character",
+		"выбрать вариант ответа в разговоре, можно просто написать номер":     "choose an answer in a conversation, or just type its number",
+		"который час":                                          "what time it is",
+		"отменить последнее действие":                          "undo the last action",
+		"повторить отменённое действие":                        "redo the undone action",
+		"что вы успели сделать":                                "what you have done",
+		"сохранить игру":                                       "save the game",
+		"загрузить сохранённую игру":                           "load a saved game",
+		"сменить язык игры: ru или en":                         "change the game language: ru or en",
+		"как показывать описание комнаты: text, ansi или json": "how to show the room description: text, ansi or json",
+		"снять надетый предмет":                                "take off a worn item",
+		"что на вас надето":                                    "what you are wearing",
+		"карта комнат, где вы побывали":                        "map of the rooms you have visited",
+		"список команд":                                        "list commands",
+	},
+}
+
+// translate переводит текст на язык игрока: сначала по переводам из описания мира,
+// так что мир может уточнить и сообщения игры, потом по каталогу.
+// Непереведённый текст остаётся русским
+func (player *Player) translate(text string) string {
+	if player.Locale == "" || player.Locale == Russian {
+		return text
+	}
+	if translated, ok := player.World.Translations[player.Locale][text]; ok {
+		return translated
+	}
+	if translated, ok := catalog[player.Locale][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Name - название или текст из описания мира в аргументах сообщения: комната, дверь,
+// хранилище, персонаж. Такие аргументы T переводит, а обычные строки - реплики, имена
+// игроков, набранные команды - оставляет как есть, даже если они совпадают с названием
+type Name string
+
+// T - сообщение на языке игрока. Аргументы-предметы и Name тоже переводятся,
+// так что названия можно передавать как есть
+func (player *Player) T(format string, args ...any) string {
+	format = player.translate(format)
+	if len(args) == 0 {
+		return format
+	}
+	args = slices.Clone(args)
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case Name:
+			args[i] = player.translate(string(arg))
+		case Item:
+			args[i] = player.translate(string(arg))
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// join переводит и перечисляет названия через запятую
+func join[T ~string](player *Player, names []T) string {
+	return strings.Join(translateAll(player, names), ", ")
+}
+
+func (player *Player) SetLocale(name string) string {
+	for _, locale := range Locales {
+		if string(locale) == name {
+			player.Locale = locale
+			return player.T("язык: %s", name)
+		}
+	}
+	return player.T("нет такого языка: %s", name)
+}
+
+// Canonical возвращает исходное русское название по переводу на язык игрока,
+// чтобы команды на английском находили объекты мира
+func (player *Player) Canonical(names []string) map[string]string {
+	canonical := make(map[string]string, len(names))
+	for _, name := range names {
+		canonical[name] = name
+		if translated := player.translate(name); translated != name {
+			canonical[translated] = name
+		}
+	}
+	return canonical
+}
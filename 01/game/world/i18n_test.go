@@ -0,0 +1,125 @@
+package world
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestCatalogVerbs проверяет, что перевод подставляет аргументы так же, как русский шаблон
+func TestCatalogVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[0-9]*[a-z]`)
+	for locale, messages := range catalog {
+		for russian, translated := range messages {
+			if !slices.Equal(verbs.FindAllString(russian, -1), verbs.FindAllString(translated, -1)) {
+				t.Errorf("%s: %q and %q have different verbs", locale, russian, translated)
+			}
+		}
+	}
+}
+
+func TestEventsLocale(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "кухня",
+		"rooms": [{"name": "кухня", "storages": [{"name_in_case": "на столе", "items": ["чай"]}]}],
+		"items": [{"name": "чай", "slot": "руки"}],
+		"translations": {"en": {"чай": "tea"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor, russian, english := world.NewPlayer(), world.NewPlayer(), world.NewPlayer()
+	actor.Name, english.Locale = "вася", English
+	heard := map[*Player]string{}
+	for _, player := range []*Player{russian, english} {
+		world.Events.Subscribe(player, func(text string) {
+			heard[player] = text
+		})
+	}
+	actor.WearItem("чай")
+	if heard[russian] != "вася надел чай" || heard[english] != "вася put on tea" {
+		t.Errorf("unexpected events: %q, %q", heard[russian], heard[english])
+	}
+	// реплики не переводятся, даже если совпадают с названием предмета
+	if answer := english.Say("чай"); answer != "you said: чай" {
+		t.Errorf("chat translated: %q", answer)
+	}
+	actor.Say("чай")
+	if heard[english] != "вася says: чай" {
+		t.Errorf("chat translated for listener: %q", heard[english])
+	}
+	if answer := english.LookAround(); answer != "empty room. you can go to - " {
+		t.Errorf("unexpected look: %q", answer)
+	}
+	if answer := english.SetLocale("fr"); answer != "no such language: fr" {
+		t.Errorf("unexpected answer: %q", answer)
+	}
+}
+
+// TestCatalogCoverage проверяет, что у каждого сообщения, которое код передаёт в T
+// и publish строкой, и у каждой справки по команде есть перевод в каждом каталоге
+func TestCatalogCoverage(t *testing.T) {
+	var files []string
+	for _, pattern := range []string{"*.go", "../*.go"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, matches...)
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check := func(literal *ast.BasicLit, prefix string) {
+			message, err := strconv.Unquote(literal.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for locale, messages := range catalog {
+				if _, ok := messages[prefix+message]; !ok {
+					t.Errorf("%s: %s: no translation for %q", fset.Position(literal.Pos()), locale, prefix+message)
+				}
+			}
+		}
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			if field, ok := node.(*ast.KeyValueExpr); ok {
+				key, isIdent := field.Key.(*ast.Ident)
+				literal, isLiteral := field.Value.(*ast.BasicLit)
+				if isIdent && key.Name == "Help" && isLiteral && literal.Kind == token.STRING {
+					check(literal, "")
+				}
+				return true
+			}
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != "T" && selector.Sel.Name != "publish" {
+				return true
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			if selector.Sel.Name == "publish" {
+				check(literal, "%s ")
+			} else {
+				check(literal, "")
+			}
+			return true
+		})
+	}
+}
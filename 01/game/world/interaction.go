@@ -0,0 +1,129 @@
+package world
+
+import "slices"
+
+// ItemType - свойства предмета. В хранилищах и инвентаре предметы остаются названиями,
+// свойства ищутся по названию в World.Items
+type ItemType struct {
+	Name       Item              `json:"name"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Weight     int               `json:"weight,omitempty"`
+	Volume     int               `json:"volume,omitempty"`
+	// подробное описание для команды осмотреть
+	Description string `json:"description,omitempty"`
+	// сколько здоровья восстанавливает, если съесть, урон оружием и защита надетого предмета
+	Heal   int `json:"heal,omitempty"`
+	Damage int `json:"damage,omitempty"`
+	Armor  int `json:"armor,omitempty"`
+	// куда предмет надевается: спина, голова, руки. Без слота предмет не надеть,
+	// у контейнеров по умолчанию DefaultSlot
+	Slot string `json:"slot,omitempty"`
+	// надетый предмет светит и греет, это проверяют условия light и warmth
+	Light  bool `json:"light,omitempty"`
+	Warmth int  `json:"warmth,omitempty"`
+	// предмет сам является хранилищем, это его начальное содержимое
+	Container *Storage `json:"container,omitempty"`
+}
+
+func (world *World) HasProperty(item Item, property string) bool {
+	itemType, ok := world.Items[item]
+	if !ok {
+		return false
+	}
+	_, ok = itemType.Properties[property]
+	return ok
+}
+
+// Interaction - что происходит, когда игрок применяет предмет к цели
+type Interaction struct {
+	Item Item `json:"item,omitempty"`
+	// вместо конкретного предмета подходит любой предмет с таким свойством
+	ItemProperty string `json:"item_property,omitempty"`
+	Target       string `json:"target"`
+	// применить можно только в этой комнате. Без комнаты цель должна быть рядом:
+	// дверь текущей комнаты или предмет в её хранилищах либо в инвентаре
+	Room string    `json:"room,omitempty"`
+	If   Condition `json:"if"`
+
+	Answer string `json:"answer,omitempty"`
+	// открыть или закрыть дверь-цель в текущей комнате, ответом служит новое состояние двери
+	ToggleDoor    bool   `json:"toggle_door,omitempty"`
+	ConsumeItem   bool   `json:"consume_item,omitempty"`
+	ConsumeTarget bool   `json:"consume_target,omitempty"`
+	Spawn         []Item `json:"spawn,omitempty"`
+	// новые описания текущей комнаты
+	SetNote           string `json:"set_note,omitempty"`
+	SetLookAroundNote string `json:"set_look_around_note,omitempty"`
+}
+
+func (interaction *Interaction) matches(player *Player, item Item, target string) bool {
+	if interaction.Target != target {
+		return false
+	}
+	if interaction.Item != "" && interaction.Item != item {
+		return false
+	}
+	if interaction.ItemProperty != "" && !player.World.HasProperty(item, interaction.ItemProperty) {
+		return false
+	}
+	room := player.CurrentRoom
+	if interaction.Room != "" {
+		if room.Name != interaction.Room {
+			return false
+		}
+	} else if !player.targetNearby(target) {
+		return false
+	}
+	// запертую дверь открывает только её собственный ключ, а не любой предмет со свойством
+	if door := room.findDoor(target); interaction.ToggleDoor && (door == nil || !door.unlocks(item)) {
+		return false
+	}
+	return interaction.If.Check(player)
+}
+
+func (player *Player) targetNearby(target string) bool {
+	if player.CurrentRoom.findDoor(target) != nil {
+		return true
+	}
+	if slices.Contains(player.Inventory, Item(target)) {
+		return true
+	}
+	_, _, ok := player.CurrentRoom.findItem(Item(target))
+	return ok
+}
+
+// apply выполняет применение и возвращает ответ и выданные предметы, которые не поместились в инвентарь
+func (interaction *Interaction) apply(player *Player, item Item, target string) (result string, dropped []Item) {
+	room := player.CurrentRoom
+	if interaction.ToggleDoor {
+		door := room.findDoor(target)
+		result = door.Toggle(item == door.Key)
+	}
+	if interaction.ConsumeItem {
+		player.dropFromInventory(item)
+	}
+	if interaction.ConsumeTarget {
+		if storage, i, ok := room.findItem(Item(target)); ok {
+			storage.Items = deleteItem(storage.Items, i)
+		} else {
+			player.dropFromInventory(Item(target))
+		}
+	}
+	dropped = player.receive(interaction.Spawn)
+	if interaction.SetNote != "" {
+		room.Note = interaction.SetNote
+	}
+	if interaction.SetLookAroundNote != "" {
+		room.LookAroundNote = interaction.SetLookAroundNote
+	}
+	if interaction.Answer != "" {
+		result = interaction.Answer
+	}
+	return
+}
+
+func (player *Player) dropFromInventory(item Item) {
+	if i := slices.Index(player.Inventory, item); i >= 0 {
+		player.Inventory = deleteItem(player.Inventory, i)
+	}
+}
@@ -0,0 +1,74 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const workshopWorld = `{
+	"start": "мастерская",
+	"rooms": [{
+		"name": "мастерская",
+		"note": "мастерская",
+		"look_around_note": "шкаф закрыт на винты",
+		"storages": [{"name_in_case": "на верстаке", "items": ["отвёртка", "чай", "чайник"]}]
+	}],
+	"items": [
+		{"name": "отвёртка", "properties": {"инструмент": "крестовая"}},
+		{"name": "сумка", "container": {"name_in_case": "в сумке"}}
+	],
+	"interactions": [
+		{"item_property": "инструмент", "target": "шкаф", "room": "мастерская",
+			"answer": "шкаф открыт", "set_look_around_note": "шкаф открыт", "spawn": ["молоток"]},
+		{"item": "чай", "target": "чайник", "answer": "чай заварен",
+			"consume_item": true, "spawn": ["заварка"]}
+	]
+}`
+
+func TestInteractions(t *testing.T) {
+	world, err := Load(strings.NewReader(workshopWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Equipment[DefaultSlot] = "сумка"
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{func() string { return player.UseItem("отвёртка", "шкаф") }, "нет предмета в инвентаре - отвёртка"},
+		{func() string { return player.TakeItem("отвёртка") }, "предмет добавлен в инвентарь: отвёртка"},
+		{func() string { return player.UseItem("отвёртка", "чайник") }, cantUse},
+		{func() string { return player.UseItem("отвёртка", "шкаф") }, "шкаф открыт"},
+		{func() string { return player.LookAround() }, "шкаф открыт, на верстаке: чай, чайник. можно пройти - "},
+		{func() string { return player.TakeItem("чай") }, "предмет добавлен в инвентарь: чай"},
+		{func() string { return player.UseItem("чай", "чайник") }, "чай заварен"},
+		{func() string { return player.UseItem("чай", "чайник") }, "нет предмета в инвентаре - чай"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	if expected := []Item{"отвёртка", "молоток", "заварка"}; !slices.Equal(player.Inventory, expected) {
+		t.Errorf("unexpected inventory %v, expected %v", player.Inventory, expected)
+	}
+}
+
+func TestInteractionsOverflow(t *testing.T) {
+	world, err := Load(strings.NewReader(strings.Replace(workshopWorld, `"в сумке"}`, `"в сумке", "capacity": 2}`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Equipment[DefaultSlot] = "сумка"
+	player.TakeItem("отвёртка")
+	player.TakeItem("чай")
+	if answer := player.UseItem("отвёртка", "шкаф"); answer != "шкаф открыт. не поместилось и осталось на полу: молоток" {
+		t.Errorf("unexpected answer: %s", answer)
+	}
+	if floor := player.CurrentRoom.Floor(); !slices.Equal(floor.Items, []Item{"молоток"}) {
+		t.Errorf("spawned item not on the floor: %v", floor.Items)
+	}
+}
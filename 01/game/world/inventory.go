@@ -0,0 +1,166 @@
+package world
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HasBackpack - есть ли на игроке контейнер, в который можно класть предметы
+func (player *Player) HasBackpack() bool {
+	_, ok := player.carryLimits()
+	return ok
+}
+
+// carryLimits складывает вместимость всех надетых контейнеров.
+// Если хоть один контейнер не ограничен по какому-то параметру, не ограничен и инвентарь
+func (player *Player) carryLimits() (limits Storage, ok bool) {
+	unlimited := [3]bool{}
+	for _, item := range player.Worn() {
+		container, isContainer := player.World.Containers[item]
+		if !isContainer {
+			continue
+		}
+		if !ok {
+			limits.Name, limits.NameInCase = container.Name, container.NameInCase
+			ok = true
+		}
+		for i, limit := range []int{container.Capacity, container.MaxWeight, container.MaxVolume} {
+			unlimited[i] = unlimited[i] || limit == 0
+		}
+		limits.Capacity += container.Capacity
+		limits.MaxWeight += container.MaxWeight
+		limits.MaxVolume += container.MaxVolume
+	}
+	if unlimited[0] {
+		limits.Capacity = 0
+	}
+	if unlimited[1] {
+		limits.MaxWeight = 0
+	}
+	if unlimited[2] {
+		limits.MaxVolume = 0
+	}
+	return
+}
+
+// canCarry возвращает причину отказа, если предмет нельзя положить в инвентарь
+func (player *Player) canCarry(item Item) (refusal string, ok bool) {
+	limits, hasBackpack := player.carryLimits()
+	if !hasBackpack {
+		return player.T("некуда класть"), false
+	}
+	if refusal = player.refusal(&limits, player.Inventory, item); refusal != "" {
+		return refusal, false
+	}
+	return "", true
+}
+
+// receive кладёт полученные предметы в инвентарь, а то, что не поместилось,
+// на пол текущей комнаты. Возвращает предметы, оставшиеся на полу
+func (player *Player) receive(items []Item) (dropped []Item) {
+	for _, item := range items {
+		if _, ok := player.canCarry(item); ok {
+			player.Inventory = append(player.Inventory, item)
+		} else {
+			dropped = append(dropped, item)
+		}
+	}
+	if len(dropped) > 0 {
+		floor := player.CurrentRoom.Floor()
+		floor.Items = append(floor.Items, dropped...)
+	}
+	return dropped
+}
+
+// droppedNote - ответ о предметах, которые не поместились в инвентарь
+func (player *Player) droppedNote(dropped []Item) string {
+	if len(dropped) == 0 {
+		return ""
+	}
+	return ". " + player.T("не поместилось и осталось на полу: %s", join(player, dropped))
+}
+
+// refusal проверяет, поместится ли предмет к уже лежащим в хранилище
+func (player *Player) refusal(storage *Storage, items []Item, item Item) string {
+	world := player.World
+	if storage.Capacity > 0 && len(items) >= storage.Capacity {
+		return player.T("%s нет места", Name(storage.NameInCase))
+	}
+	if storage.MaxWeight > 0 && world.Weight(items)+world.Weight([]Item{item}) > storage.MaxWeight {
+		return player.T("слишком тяжело")
+	}
+	if storage.MaxVolume > 0 && world.Volume(items)+world.Volume([]Item{item}) > storage.MaxVolume {
+		return player.T("не влезает")
+	}
+	return ""
+}
+
+// Weight считает вес предметов вместе с содержимым контейнеров
+func (world *World) Weight(items []Item) (weight int) {
+	for _, item := range items {
+		if itemType, ok := world.Items[item]; ok {
+			weight += itemType.Weight
+		}
+		if container, ok := world.Containers[item]; ok {
+			weight += world.Weight(container.Items)
+		}
+	}
+	return
+}
+
+func (world *World) Volume(items []Item) (volume int) {
+	for _, item := range items {
+		if itemType, ok := world.Items[item]; ok {
+			volume += itemType.Volume
+		}
+	}
+	return
+}
+
+func (player *Player) ShowInventory() string {
+	if len(player.Inventory) == 0 {
+		return player.T("инвентарь пуст")
+	}
+	items := make([]string, 0, len(player.Inventory))
+	for _, item := range player.Inventory {
+		items = append(items, player.describeItem(item))
+	}
+	result := player.T("в инвентаре: %s", strings.Join(items, ", "))
+
+	limits, _ := player.carryLimits()
+	var stats []string
+	if limits.Capacity > 0 {
+		stats = append(stats, player.T("предметов %s", usage(len(player.Inventory), limits.Capacity)))
+	}
+	if weight := player.World.Weight(player.Inventory); weight > 0 || limits.MaxWeight > 0 {
+		stats = append(stats, player.T("вес %s", usage(weight, limits.MaxWeight)))
+	}
+	if volume := player.World.Volume(player.Inventory); volume > 0 || limits.MaxVolume > 0 {
+		stats = append(stats, player.T("объём %s", usage(volume, limits.MaxVolume)))
+	}
+	if len(stats) > 0 {
+		result += ". " + strings.Join(stats, ", ")
+	}
+	return result
+}
+
+func usage(used, limit int) string {
+	if limit == 0 {
+		return strconv.Itoa(used)
+	}
+	return strconv.Itoa(used) + "/" + strconv.Itoa(limit)
+}
+
+// DropItem выбрасывает предмет из инвентаря на пол текущей комнаты
+func (player *Player) DropItem(item Item) string {
+	i := slices.Index(player.Inventory, item)
+	if i < 0 {
+		return player.T("нет предмета в инвентаре - %s", item)
+	}
+	player.Inventory = deleteItem(player.Inventory, i)
+	floor := player.CurrentRoom.Floor()
+	floor.Items = append(floor.Items, item)
+	player.publish("выбросил %s", item)
+	return player.T("вы выбросили: %s", item)
+}
@@ -1,19 +1,41 @@
 package world
 
+import "slices"
+
 func deleteItem(slice []Item, index int) []Item {
 	return append(slice[:index], slice[index+1:]...)
 }
 
-type Task struct {
-	Text string
-	Done func() bool
-}
-
 type Item string
 
 type RoomItems map[string]string
 
+// Storage - место, где лежат предметы: стол, шкаф или предмет-контейнер вроде рюкзака
 type Storage struct {
-	NameInCase string
-	Items      []Item
+	// название для команд открыть/положить/взять из, у предметов-контейнеров совпадает с предметом
+	Name       string `json:"name,omitempty"`
+	NameInCase string `json:"name_in_case"`
+	Items      []Item `json:"items"`
+	// закрытое хранилище не показывает содержимое, запертое открывается только ключом Key
+	Closable bool `json:"closable,omitempty"`
+	Closed   bool `json:"closed,omitempty"`
+	Locked   bool `json:"locked,omitempty"`
+	Key      Item `json:"key,omitempty"`
+	// сколько предметов помещается и какой суммарный вес и объём, 0 - без ограничений
+	Capacity  int `json:"capacity,omitempty"`
+	MaxWeight int `json:"max_weight,omitempty"`
+	MaxVolume int `json:"max_volume,omitempty"`
+	// что игрок видит, осматривая хранилище, и описания лежащих в нём предметов,
+	// если здесь они выглядят иначе, чем обычно
+	Description  string          `json:"description,omitempty"`
+	Descriptions map[Item]string `json:"descriptions,omitempty"`
+	// спрятанные предметы не видны, пока игрок не осмотрит открытое хранилище
+	Hidden []Item `json:"hidden,omitempty"`
+}
+
+func (storage *Storage) Clone() *Storage {
+	clone := *storage
+	clone.Items = slices.Clone(storage.Items)
+	clone.Hidden = slices.Clone(storage.Hidden)
+	return &clone
 }
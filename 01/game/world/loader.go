@@ -0,0 +1,537 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Формат файла описания мира
+
+type worldFile struct {
+	Start string      `json:"start"`
+	Rooms []roomFile  `json:"rooms"`
+	Doors []doorFile  `json:"doors,omitempty"`
+	Items []*ItemType `json:"items,omitempty"`
+	// применения предметов проверяются в порядке описания
+	Interactions []*Interaction `json:"interactions,omitempty"`
+	Quests       []*Quest       `json:"quests,omitempty"`
+	Time         timeFile       `json:"time"`
+	Player       *Stats         `json:"player,omitempty"`
+	Combat       combatFile     `json:"combat"`
+	// переводы текстов мира по языкам, ключ - русский текст
+	Translations map[Locale]map[string]string `json:"translations,omitempty"`
+}
+
+type combatFile struct {
+	Seed        int64  `json:"seed,omitempty"`
+	Respawn     string `json:"respawn,omitempty"`
+	DropOnDeath bool   `json:"drop_on_death,omitempty"`
+}
+
+// timeFile - настройки игровых часов
+type timeFile struct {
+	// время суток в начале игры, по умолчанию 08:00
+	Start string `json:"start,omitempty"`
+	// минут на команду, по умолчанию 1
+	PerCommand    *int   `json:"per_command,omitempty"`
+	DaysPerSeason int    `json:"days_per_season,omitempty"`
+	Season        string `json:"season,omitempty"`
+}
+
+type roomFile struct {
+	Name           string        `json:"name"`
+	Note           string        `json:"note,omitempty"`
+	LookAroundNote string        `json:"look_around_note,omitempty"`
+	Description    string        `json:"description,omitempty"`
+	Storages       []*Storage    `json:"storages,omitempty"`
+	Exits          []exitFile    `json:"exits,omitempty"`
+	Commands       []commandFile `json:"commands,omitempty"`
+	NPCs           []*NPC        `json:"npcs,omitempty"`
+	Events         []*TimedEvent `json:"events,omitempty"`
+	Hooks          []*Hook       `json:"hooks,omitempty"`
+}
+
+// commandFile - команда комнаты, которая просто отвечает заданным текстом
+type commandFile struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Help    string   `json:"help,omitempty"`
+	Answer  string   `json:"answer,omitempty"`
+}
+
+type exitFile struct {
+	To string `json:"to"`
+	// по умолчанию совпадает с To
+	Name    string    `json:"name,omitempty"`
+	Door    string    `json:"door,omitempty"`
+	OneWay  bool      `json:"one_way,omitempty"`
+	If      Condition `json:"if"`
+	Refusal string    `json:"refusal,omitempty"`
+}
+
+type doorFile struct {
+	ID string `json:"id"`
+	// по умолчанию "дверь"
+	Name        string `json:"name,omitempty"`
+	Closed      bool   `json:"closed,omitempty"`
+	Locked      bool   `json:"locked,omitempty"`
+	Key         Item   `json:"key,omitempty"`
+	Open        string `json:"open_note,omitempty"`
+	Close       string `json:"closed_note,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func LoadFile(path string) (*World, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file)
+}
+
+// Load разбирает описание мира и проверяет его целостность
+func Load(r io.Reader) (*World, error) {
+	var data worldFile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&data); err != nil {
+		return nil, fmt.Errorf("cant decode world: %s", err)
+	}
+	return data.build()
+}
+
+func (data *worldFile) build() (*World, error) {
+	world := &World{Rooms: make(map[string]*Room, len(data.Rooms))}
+	for _, roomData := range data.Rooms {
+		if roomData.Name == "" {
+			return nil, fmt.Errorf("room without name")
+		}
+		if _, ok := world.Rooms[roomData.Name]; ok {
+			return nil, fmt.Errorf("duplicate room %q", roomData.Name)
+		}
+		room := &Room{
+			Name:           roomData.Name,
+			Note:           roomData.Note,
+			LookAroundNote: roomData.LookAroundNote,
+			Description:    roomData.Description,
+		}
+		for _, commandData := range roomData.Commands {
+			answer := commandData.Answer
+			// игрок набирает команды в любом регистре, разбор приводит их к нижнему
+			aliases := make([]string, 0, len(commandData.Aliases))
+			for _, alias := range commandData.Aliases {
+				aliases = append(aliases, strings.ToLower(alias))
+			}
+			err := room.Commands.Register(&Command{
+				Name:    strings.ToLower(commandData.Name),
+				Aliases: aliases,
+				Help:    commandData.Help,
+				Rest:    true,
+				Handler: func(player *Player, args []string) string {
+					return answer
+				},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("room %q: %s", room.Name, err)
+			}
+		}
+		for _, storage := range roomData.Storages {
+			if err := checkStorage(storage); err != nil {
+				return nil, fmt.Errorf("room %q, storage %q: %s", room.Name, storage.NameInCase, err)
+			}
+			room.Storages = append(room.Storages, storage.Clone())
+		}
+		// пол создаётся заранее, чтобы набор хранилищ комнаты не менялся во время игры
+		room.Floor()
+		world.Rooms[room.Name] = room
+	}
+
+	for i, doorData := range data.Doors {
+		if err := world.addDoor(doorData); err != nil {
+			return nil, fmt.Errorf("door %d: %s", i, err)
+		}
+	}
+	// задания нужны раньше проходов и обработчиков комнат: на них ссылаются условия quest_done
+	for _, quest := range data.Quests {
+		if err := world.checkQuest(quest); err != nil {
+			return nil, fmt.Errorf("quest %q: %s", quest.Name, err)
+		}
+		world.Quests = append(world.Quests, quest)
+	}
+	// проходы разрешаются, когда все комнаты и двери уже созданы
+	for _, roomData := range data.Rooms {
+		room := world.Rooms[roomData.Name]
+		for _, exitData := range roomData.Exits {
+			if err := world.addExit(room, exitData); err != nil {
+				return nil, fmt.Errorf("room %q, exit %q: %s", room.Name, exitData.To, err)
+			}
+		}
+	}
+	if err := world.checkExits(); err != nil {
+		return nil, err
+	}
+	for _, roomData := range data.Rooms {
+		room := world.Rooms[roomData.Name]
+		for _, npc := range roomData.NPCs {
+			if err := world.checkNPC(npc); err != nil {
+				return nil, fmt.Errorf("room %q, npc %q: %s", room.Name, npc.Name, err)
+			}
+			if room.findNPC(npc.Name) != nil {
+				return nil, fmt.Errorf("room %q: duplicate npc %q", room.Name, npc.Name)
+			}
+			room.NPCs = append(room.NPCs, npc)
+		}
+		for i, event := range roomData.Events {
+			if err := world.checkTimedEvent(event); err != nil {
+				return nil, fmt.Errorf("room %q, event %d: %s", room.Name, i, err)
+			}
+			room.Events = append(room.Events, event)
+		}
+		for i, hook := range roomData.Hooks {
+			if err := world.checkHook(hook); err != nil {
+				return nil, fmt.Errorf("room %q, hook %d: %s", room.Name, i, err)
+			}
+			room.Hooks = append(room.Hooks, hook)
+		}
+	}
+	if err := world.setClock(data.Time); err != nil {
+		return nil, fmt.Errorf("time: %s", err)
+	}
+
+	world.Items = make(map[Item]*ItemType, len(data.Items))
+	world.Containers = make(map[Item]*Storage)
+	for _, itemType := range data.Items {
+		if itemType.Name == "" {
+			return nil, fmt.Errorf("item without name")
+		}
+		if _, ok := world.Items[itemType.Name]; ok {
+			return nil, fmt.Errorf("duplicate item %q", itemType.Name)
+		}
+		if itemType.Heal < 0 || itemType.Damage < 0 || itemType.Armor < 0 || itemType.Warmth < 0 {
+			return nil, fmt.Errorf("item %q: negative effects", itemType.Name)
+		}
+		if itemType.Slot == "" && itemType.Container != nil {
+			itemType.Slot = DefaultSlot
+		}
+		if itemType.Slot == "" && (itemType.Armor > 0 || itemType.Warmth > 0 || itemType.Light) {
+			return nil, fmt.Errorf("item %q: effects of worn item without slot", itemType.Name)
+		}
+		world.Items[itemType.Name] = itemType
+		if itemType.Container != nil {
+			container := itemType.Container.Clone()
+			container.Name = string(itemType.Name)
+			if slices.Contains(container.Items, itemType.Name) || slices.Contains(container.Hidden, itemType.Name) {
+				return nil, fmt.Errorf("item %q contains itself", itemType.Name)
+			}
+			if err := checkStorage(container); err != nil {
+				return nil, fmt.Errorf("item %q: %s", itemType.Name, err)
+			}
+			world.Containers[itemType.Name] = container
+		}
+	}
+	for i, interaction := range data.Interactions {
+		if err := world.checkInteraction(interaction); err != nil {
+			return nil, fmt.Errorf("interaction %d: %s", i, err)
+		}
+	}
+	world.Interactions = data.Interactions
+	if err := world.checkContainerCopies(data); err != nil {
+		return nil, err
+	}
+
+	start, ok := world.Rooms[data.Start]
+	if !ok {
+		return nil, fmt.Errorf("unknown start room %q", data.Start)
+	}
+	world.Start = start
+	if err := world.setCombat(data.Player, data.Combat); err != nil {
+		return nil, err
+	}
+	for locale := range data.Translations {
+		if !slices.Contains(Locales, locale) {
+			return nil, fmt.Errorf("translations: unknown locale %q", locale)
+		}
+	}
+	world.Translations = data.Translations
+	return world, nil
+}
+
+func (world *World) setCombat(stats *Stats, data combatFile) error {
+	// по умолчанию игрок может драться, даже если мир этого не описывает
+	world.PlayerStats = Stats{Health: 10, Strength: 1}
+	if stats != nil {
+		if err := checkStats(*stats); err != nil {
+			return fmt.Errorf("player: %s", err)
+		}
+		if stats.Health == 0 {
+			return fmt.Errorf("player: zero health")
+		}
+		world.PlayerStats = *stats
+	}
+	world.Combat = CombatRules{Respawn: world.Start, DropOnDeath: data.DropOnDeath}
+	if data.Respawn != "" {
+		if world.Combat.Respawn = world.Rooms[data.Respawn]; world.Combat.Respawn == nil {
+			return fmt.Errorf("combat: unknown respawn room %q", data.Respawn)
+		}
+	}
+	world.setRand(data.Seed)
+	return nil
+}
+
+func (world *World) setClock(data timeFile) (err error) {
+	world.Clock = Clock{Start: 8 * 60, PerCommand: 1, DaysPerSeason: data.DaysPerSeason}
+	if data.Start != "" {
+		if world.Clock.Start, err = parseTimeOfDay(data.Start); err != nil {
+			return err
+		}
+	}
+	if data.PerCommand != nil {
+		if *data.PerCommand < 0 {
+			return fmt.Errorf("negative per_command")
+		}
+		world.Clock.PerCommand = *data.PerCommand
+	}
+	if data.DaysPerSeason < 0 {
+		return fmt.Errorf("negative days_per_season")
+	}
+	if data.Season != "" {
+		if world.Clock.StartSeason = slices.Index(seasons, data.Season); world.Clock.StartSeason < 0 {
+			return fmt.Errorf("unknown season %q", data.Season)
+		}
+	}
+	return nil
+}
+
+func (world *World) checkCondition(cond Condition) error {
+	if slices.Contains(cond.HasItems, "") || slices.Contains(cond.Wears, "") {
+		return fmt.Errorf("empty item in condition")
+	}
+	for _, name := range []string{cond.InRoom, cond.Visited} {
+		if name != "" && world.Rooms[name] == nil {
+			return fmt.Errorf("unknown room %q in condition", name)
+		}
+	}
+	if cond.DoorOpen != "" && world.Door(cond.DoorOpen) == nil {
+		return fmt.Errorf("unknown door %q in condition", cond.DoorOpen)
+	}
+	if cond.QuestDone != "" && world.quest(cond.QuestDone) == nil {
+		return fmt.Errorf("unknown quest %q in condition", cond.QuestDone)
+	}
+	return nil
+}
+
+// checkContainerCopies проверяет, что каждый предмет-контейнер появляется в мире не больше
+// одного раза: содержимое контейнера хранится по его названию, и две копии делили бы его.
+// Появления, которые могут повторяться, - применения предметов и ответы в разговорах -
+// контейнеры не выдают вовсе
+func (world *World) checkContainerCopies(data *worldFile) error {
+	copies := make(map[Item]int, len(world.Containers))
+	count := func(items []Item) {
+		for _, item := range items {
+			if _, ok := world.Containers[item]; ok {
+				copies[item]++
+			}
+		}
+	}
+	for _, roomData := range data.Rooms {
+		for _, storage := range roomData.Storages {
+			count(storage.Items)
+			count(storage.Hidden)
+		}
+		for _, npc := range roomData.NPCs {
+			count(npc.Loot)
+			for _, line := range npc.Lines {
+				for _, choice := range line.Choices {
+					if slices.ContainsFunc(choice.Give, world.isContainer) {
+						return fmt.Errorf("npc %q gives a container", npc.Name)
+					}
+				}
+			}
+		}
+	}
+	for _, container := range world.Containers {
+		count(container.Items)
+		count(container.Hidden)
+	}
+	for _, quest := range data.Quests {
+		count(quest.Reward.Items)
+	}
+	for i, interaction := range data.Interactions {
+		if slices.ContainsFunc(interaction.Spawn, world.isContainer) {
+			return fmt.Errorf("interaction %d spawns a container", i)
+		}
+	}
+	for item, n := range copies {
+		if n > 1 {
+			return fmt.Errorf("container %q placed %d times", item, n)
+		}
+	}
+	return nil
+}
+
+func (world *World) isContainer(item Item) bool {
+	_, ok := world.Containers[item]
+	return ok
+}
+
+func checkStorage(storage *Storage) error {
+	if (storage.Closed || storage.Locked) && !storage.Closable {
+		return fmt.Errorf("closed storage must be closable")
+	}
+	if storage.Locked && (!storage.Closed || storage.Key == "") {
+		return fmt.Errorf("locked storage must be closed and have a key")
+	}
+	if storage.Closable && storage.Name == "" {
+		return fmt.Errorf("closable storage must have a name")
+	}
+	if len(storage.Hidden) > 0 && storage.Name == "" {
+		return fmt.Errorf("storage with hidden items must have a name")
+	}
+	if slices.Contains(storage.Hidden, "") {
+		return fmt.Errorf("empty hidden item")
+	}
+	if storage.Capacity > 0 && len(storage.Items)+len(storage.Hidden) > storage.Capacity {
+		return fmt.Errorf("storage holds %d items, capacity is %d", len(storage.Items)+len(storage.Hidden), storage.Capacity)
+	}
+	return nil
+}
+
+// checkQuest вызывается по порядку, поэтому After может ссылаться только на задания выше
+func (world *World) checkQuest(quest *Quest) error {
+	if quest.Name == "" {
+		return fmt.Errorf("quest without name")
+	}
+	if world.quest(quest.Name) != nil {
+		return fmt.Errorf("duplicate quest")
+	}
+	if quest.Room != "" && world.Rooms[quest.Room] == nil {
+		return fmt.Errorf("unknown room %q", quest.Room)
+	}
+	if quest.After != "" && world.quest(quest.After) == nil {
+		return fmt.Errorf("unknown previous quest %q", quest.After)
+	}
+	if len(quest.Steps) == 0 {
+		return fmt.Errorf("quest without steps")
+	}
+	for _, step := range quest.Steps {
+		if err := world.checkCondition(step.Done); err != nil {
+			return fmt.Errorf("step %q: %s", step.Text, err)
+		}
+	}
+	if slices.Contains(quest.Reward.Items, "") {
+		return fmt.Errorf("empty reward item")
+	}
+	return nil
+}
+
+func (world *World) checkInteraction(interaction *Interaction) error {
+	if (interaction.Item == "") == (interaction.ItemProperty == "") {
+		return fmt.Errorf("exactly one of item and item_property must be set")
+	}
+	if interaction.Target == "" {
+		return fmt.Errorf("empty target")
+	}
+	if interaction.Room != "" && world.Rooms[interaction.Room] == nil {
+		return fmt.Errorf("unknown room %q", interaction.Room)
+	}
+	if interaction.Answer == "" && !interaction.ToggleDoor {
+		return fmt.Errorf("interaction has no answer")
+	}
+	if slices.Contains(interaction.Spawn, "") {
+		return fmt.Errorf("empty spawned item")
+	}
+	return world.checkCondition(interaction.If)
+}
+
+func (world *World) addDoor(doorData doorFile) error {
+	if doorData.ID == "" {
+		return fmt.Errorf("door without id")
+	}
+	if world.Door(doorData.ID) != nil {
+		return fmt.Errorf("duplicate door %q", doorData.ID)
+	}
+	if doorData.Locked && (!doorData.Closed || doorData.Key == "") {
+		return fmt.Errorf("locked door must be closed and have a key")
+	}
+	door := &Door{
+		ID:          doorData.ID,
+		Name:        doorData.Name,
+		IsClosed:    doorData.Closed,
+		Locked:      doorData.Locked,
+		Key:         doorData.Key,
+		States:      map[bool]string{true: doorData.Close, false: doorData.Open},
+		Description: doorData.Description,
+	}
+	if door.Name == "" {
+		door.Name = "дверь"
+	}
+	world.Doors = append(world.Doors, door)
+	return nil
+}
+
+func (world *World) addExit(room *Room, exitData exitFile) error {
+	exit := &Exit{
+		Name:    exitData.Name,
+		To:      world.Rooms[exitData.To],
+		OneWay:  exitData.OneWay,
+		If:      exitData.If,
+		Refusal: exitData.Refusal,
+	}
+	if exit.To == nil {
+		return fmt.Errorf("unknown room")
+	}
+	if exit.Name == "" {
+		exit.Name = exit.To.Name
+	}
+	if room.findExit(exit.Name) != nil {
+		return fmt.Errorf("duplicate exit %q", exit.Name)
+	}
+	if exitData.Door != "" {
+		if exit.Door = world.Door(exitData.Door); exit.Door == nil {
+			return fmt.Errorf("unknown door %q", exitData.Door)
+		}
+	}
+	if err := world.checkCondition(exit.If); err != nil {
+		return err
+	}
+	if exit.Refusal == "" {
+		exit.Refusal = "туда нельзя"
+	}
+	room.Exits = append(room.Exits, exit)
+	return nil
+}
+
+// checkExits проверяет, что у двусторонних проходов есть обратный путь,
+// а каждая дверь стоит между одной и той же парой комнат
+func (world *World) checkExits() error {
+	doorRooms := make(map[*Door][2]*Room)
+	for _, room := range world.Rooms {
+		for _, exit := range room.Exits {
+			if !exit.OneWay && !exit.To.leadsTo(room) {
+				return fmt.Errorf("room %q, exit %q: no way back, mark exit as one_way", room.Name, exit.Name)
+			}
+			if exit.Door == nil {
+				continue
+			}
+			pair := [2]*Room{room, exit.To}
+			if room.Name > exit.To.Name {
+				pair = [2]*Room{exit.To, room}
+			}
+			if known, ok := doorRooms[exit.Door]; ok && known != pair {
+				return fmt.Errorf("door %q connects different rooms", exit.Door.ID)
+			}
+			doorRooms[exit.Door] = pair
+		}
+	}
+	for _, door := range world.Doors {
+		if _, ok := doorRooms[door]; !ok {
+			return fmt.Errorf("door %q is not used by any exit", door.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadInvalid(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"unknown start", `{"start": "нигде", "rooms": [{"name": "кухня"}]}`},
+		{"duplicate room", `{"start": "кухня", "rooms": [{"name": "кухня"}, {"name": "кухня"}]}`},
+		{"unknown exit room", `{"start": "кухня", "rooms": [{"name": "кухня", "exits": [{"to": "коридор"}]}]}`},
+		{"no way back", `{"start": "кухня", "rooms": [{"name": "кухня", "exits": [{"to": "коридор"}]}, {"name": "коридор"}]}`},
+		{"unknown door", `{"start": "кухня", "rooms": [{"name": "кухня", "exits": [{"to": "кухня", "door": "входная"}]}]}`},
+		{"unknown quest room", `{"start": "кухня", "rooms": [{"name": "кухня"}],
+			"quests": [{"name": "в универ", "steps": [{"text": "идти", "done": {"in_room": "универ"}}]}]}`},
+		{"unknown previous quest", `{"start": "кухня", "rooms": [{"name": "кухня"}],
+			"quests": [{"name": "в универ", "after": "завтрак", "steps": [{"text": "идти"}]}]}`},
+		{"unused door", `{"start": "кухня", "rooms": [{"name": "кухня"}], "doors": [{"id": "входная"}]}`},
+		{"door between different rooms", `{"start": "кухня", "rooms": [
+			{"name": "кухня", "exits": [{"to": "коридор", "door": "d"}, {"to": "комната", "door": "d"}]},
+			{"name": "коридор", "exits": [{"to": "кухня", "door": "d"}]},
+			{"name": "комната", "exits": [{"to": "кухня", "door": "d"}]}
+		], "doors": [{"id": "d"}]}`},
+		{"hidden items in unnamed storage", `{"start": "кухня", "rooms": [{"name": "кухня",
+			"storages": [{"name_in_case": "на столе", "hidden": ["записка"]}]}]}`},
+		{"container placed twice", `{"start": "кухня", "rooms": [{"name": "кухня",
+			"storages": [{"name_in_case": "на столе", "items": ["сумка", "сумка"]}]}],
+			"items": [{"name": "сумка", "container": {"name_in_case": "в сумке"}}]}`},
+		{"spawned container", `{"start": "кухня", "rooms": [{"name": "кухня"}],
+			"items": [{"name": "сумка", "container": {"name_in_case": "в сумке"}}],
+			"interactions": [{"item": "ключ", "target": "дверь", "answer": "ок", "spawn": ["сумка"]}]}`},
+		{"unknown field", `{"start": "кухня", "rooms": [{"name": "кухня", "next_rooms": []}]}`},
+	}
+	for _, c := range cases {
+		if _, err := Load(strings.NewReader(c.data)); err == nil {
+			t.Errorf("[%s] expected error", c.name)
+		}
+	}
+}
+
+func TestLoadCapacity(t *testing.T) {
+	// спрятанные предметы тоже занимают место
+	_, err := Load(strings.NewReader(`{"start": "кухня", "rooms": [{"name": "кухня",
+		"storages": [{"name": "стол", "name_in_case": "на столе", "items": ["чай"], "hidden": ["записка", "ключ"], "capacity": 2}]}]}`))
+	if err == nil || !strings.Contains(err.Error(), "storage holds 3 items, capacity is 2") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestLoadRoomCommandCase(t *testing.T) {
+	world, err := Load(strings.NewReader(`{"start": "кухня", "rooms": [{"name": "кухня",
+		"commands": [{"name": "Петь", "aliases": ["ПОЙ"], "answer": "ля-ля"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	for _, name := range []string{"петь", "пой"} {
+		if command := world.Rooms["кухня"].Commands.Find(name); command == nil || command.Run(player, nil) != "ля-ля" {
+			t.Errorf("room command %q not found", name)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Map - карта комнат, где побывал игрок, деревом от стартовой комнаты.
+// Соседние комнаты, где игрок ещё не был, показываются как "?", текущая отмечена "*"
+func (player *Player) Map() string {
+	lines := []string{player.mapRoom(player.World.Start)}
+	shown := map[*Room]bool{player.World.Start: true}
+	player.mapExits(player.World.Start, "", shown, &lines)
+	return strings.Join(lines, "\n")
+}
+
+func (player *Player) mapRoom(room *Room) string {
+	name := player.T(room.Name)
+	if room == player.CurrentRoom {
+		name += " *"
+	}
+	return name
+}
+
+func (player *Player) mapExits(room *Room, indent string, shown map[*Room]bool, lines *[]string) {
+	// сначала отмечаем всех соседей, чтобы комната попала в дерево один раз, на минимальной глубине
+	var children []*Exit
+	for _, exit := range room.Exits {
+		if !shown[exit.To] {
+			shown[exit.To] = true
+			children = append(children, exit)
+		}
+	}
+	for i, exit := range children {
+		branch, next := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, next = "└─ ", "   "
+		}
+		line := indent + branch
+		if !player.Visited[exit.To.Name] {
+			*lines = append(*lines, line+"?")
+			continue
+		}
+		line += player.mapRoom(exit.To)
+		if exit.Door != nil {
+			line += " (" + player.T(exit.Door.Name) + ")"
+		}
+		*lines = append(*lines, line)
+		player.mapExits(exit.To, indent+next, shown, lines)
+	}
+}
+
+// DOT - граф мира в формате Graphviz: двусторонние проходы рисуются одной линией,
+// запертые двери пунктиром
+func (world *World) DOT() string {
+	var out strings.Builder
+	out.WriteString("digraph world {\n\tnode [shape=box];\n")
+	fmt.Fprintf(&out, "\t%q [style=bold];\n", world.Start.Name)
+	drawn := make(map[[2]*Room]bool)
+	for _, room := range world.sortedRooms() {
+		for _, exit := range room.Exits {
+			if exit.To == nil || drawn[[2]*Room{room, exit.To}] {
+				continue
+			}
+			drawn[[2]*Room{room, exit.To}] = true
+			var attrs []string
+			if exit.To.leadsTo(room) {
+				drawn[[2]*Room{exit.To, room}] = true
+				attrs = append(attrs, "dir=none")
+			}
+			if exit.Door != nil {
+				attrs = append(attrs, fmt.Sprintf("label=%q", exit.Door.Name))
+				if exit.Door.Locked {
+					attrs = append(attrs, "style=dashed")
+				}
+			}
+			fmt.Fprintf(&out, "\t%q -> %q", room.Name, exit.To.Name)
+			if len(attrs) > 0 {
+				fmt.Fprintf(&out, " [%s]", strings.Join(attrs, ", "))
+			}
+			out.WriteString(";\n")
+		}
+	}
+	out.WriteString("}\n")
+	return out.String()
+}
+
+// sortedRooms - комнаты в порядке обхода от старта, затем недостижимые по названиям
+func (world *World) sortedRooms() []*Room {
+	rooms := []*Room{world.Start}
+	seen := map[*Room]bool{world.Start: true}
+	for i := 0; i < len(rooms); i++ {
+		for _, exit := range rooms[i].Exits {
+			if exit.To != nil && !seen[exit.To] {
+				seen[exit.To] = true
+				rooms = append(rooms, exit.To)
+			}
+		}
+	}
+	var rest []string
+	for name, room := range world.Rooms {
+		if !seen[room] {
+			rest = append(rest, name)
+		}
+	}
+	slices.Sort(rest)
+	for _, name := range rest {
+		rooms = append(rooms, world.Rooms[name])
+	}
+	return rooms
+}
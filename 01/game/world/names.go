@@ -0,0 +1,62 @@
+package world
+
+// Названия объектов, которые игрок может упомянуть в командах.
+// По ним разбор команд сопоставляет словоформы и ищет опечатки
+
+// RoomNames - проходы из текущей комнаты и все комнаты мира
+func (player *Player) RoomNames() []string {
+	names := make([]string, 0, len(player.CurrentRoom.Exits)+len(player.World.Rooms))
+	for _, exit := range player.CurrentRoom.Exits {
+		names = append(names, exit.Name)
+	}
+	for name := range player.World.Rooms {
+		names = append(names, name)
+	}
+	return names
+}
+
+// ItemNames - предметы на виду, в инвентаре, на игроке и все описанные в мире предметы
+func (player *Player) ItemNames() []string {
+	var names []string
+	var addItems func(items []Item)
+	addItems = func(items []Item) {
+		for _, item := range items {
+			names = append(names, string(item))
+			if container, ok := player.World.Containers[item]; ok && !container.Closed {
+				addItems(container.Items)
+			}
+		}
+	}
+	for _, storage := range player.CurrentRoom.Storages {
+		if !storage.Closed {
+			addItems(storage.Items)
+		}
+	}
+	addItems(player.Inventory)
+	addItems(player.Worn())
+	for item := range player.World.Items {
+		names = append(names, string(item))
+	}
+	return names
+}
+
+// TargetNames - всё, к чему можно применить предмет, что можно открыть или атаковать
+func (player *Player) TargetNames() []string {
+	names := append(player.ItemNames(), player.NPCNames()...)
+	for _, storage := range player.CurrentRoom.Storages {
+		if storage.Name != "" {
+			names = append(names, storage.Name)
+		} else if word := placeWord(storage); word != "" {
+			names = append(names, word)
+		}
+	}
+	for _, exit := range player.CurrentRoom.Exits {
+		if exit.Door != nil {
+			names = append(names, exit.Door.Name)
+		}
+	}
+	for _, interaction := range player.World.Interactions {
+		names = append(names, interaction.Target)
+	}
+	return names
+}
@@ -0,0 +1,179 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NPC - персонаж в комнате, с которым можно поговорить или подраться
+type NPC struct {
+	Name string `json:"name"`
+	// реплика, с которой начинается разговор, без реплик персонаж молчит
+	Start string                   `json:"start"`
+	Lines map[string]*DialogueLine `json:"lines"`
+	Stats
+	// враждебный персонаж нападает на каждого, кто входит в комнату
+	Hostile bool `json:"hostile,omitempty"`
+	// что остаётся на полу после победы над персонажем
+	Loot   []Item `json:"loot,omitempty"`
+	Defeat string `json:"defeat,omitempty"`
+	// текущее здоровье, побеждённый персонаж пропадает из комнаты
+	HP int `json:"-"`
+}
+
+type DialogueLine struct {
+	Text    string            `json:"text"`
+	Choices []*DialogueChoice `json:"choices"`
+}
+
+// DialogueChoice - вариант ответа игрока. Вариант показывается, только если выполнено
+// условие и у игрока есть всё, что персонаж забирает
+type DialogueChoice struct {
+	Text string    `json:"text"`
+	If   Condition `json:"if"`
+	// следующая реплика персонажа, без неё разговор заканчивается
+	Next string `json:"next,omitempty"`
+	Give []Item `json:"give,omitempty"`
+	Take []Item `json:"take,omitempty"`
+	// отметка, на которую могут ссылаться условия заданий
+	SetFlag string `json:"set_flag,omitempty"`
+}
+
+// Dialogue - текущий разговор игрока
+type Dialogue struct {
+	NPC  *NPC
+	Line string
+}
+
+func (room *Room) findNPC(name string) *NPC {
+	for _, npc := range room.NPCs {
+		if npc.Name == name && !npc.defeated() {
+			return npc
+		}
+	}
+	return nil
+}
+
+func (choice *DialogueChoice) available(player *Player) bool {
+	for _, item := range choice.Take {
+		if !slices.Contains(player.Inventory, item) {
+			return false
+		}
+	}
+	return choice.If.Check(player)
+}
+
+func (player *Player) availableChoices() []*DialogueChoice {
+	line := player.Dialogue.NPC.Lines[player.Dialogue.Line]
+	choices := make([]*DialogueChoice, 0, len(line.Choices))
+	for _, choice := range line.Choices {
+		if choice.available(player) {
+			choices = append(choices, choice)
+		}
+	}
+	return choices
+}
+
+// renderLine показывает реплику персонажа и пронумерованные варианты ответа
+func (player *Player) renderLine() string {
+	npc := player.Dialogue.NPC
+	lines := []string{player.T(npc.Name) + ": " + player.T(npc.Lines[player.Dialogue.Line].Text)}
+	choices := player.availableChoices()
+	for i, choice := range choices {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, player.T(choice.Text)))
+	}
+	if len(choices) == 0 {
+		player.Dialogue = nil
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (player *Player) Talk(name string) string {
+	npc := player.CurrentRoom.findNPC(name)
+	if npc == nil {
+		return player.T("здесь нет %s", Name(name))
+	}
+	if len(npc.Lines) == 0 {
+		return player.T("%s не хочет разговаривать", Name(npc.Name))
+	}
+	player.Dialogue = &Dialogue{NPC: npc, Line: npc.Start}
+	player.publish("разговаривает: %s", Name(npc.Name))
+	return player.renderLine()
+}
+
+func (player *Player) Answer(number string) string {
+	if player.Dialogue == nil {
+		return player.T("вы ни с кем не разговариваете")
+	}
+	choices := player.availableChoices()
+	i, err := strconv.Atoi(number)
+	if err != nil || i < 1 || i > len(choices) {
+		return player.T("нет такого варианта ответа")
+	}
+	choice := choices[i-1]
+	for _, item := range choice.Take {
+		player.dropFromInventory(item)
+	}
+	dropped := player.receive(choice.Give)
+	if choice.SetFlag != "" {
+		player.Flags[choice.SetFlag] = true
+	}
+	var result string
+	if choice.Next == "" {
+		player.Dialogue = nil
+		result = player.T("разговор окончен")
+	} else {
+		player.Dialogue.Line = choice.Next
+		result = player.renderLine()
+	}
+	if len(dropped) > 0 {
+		result += "\n" + player.T("не поместилось и осталось на полу: %s", join(player, dropped))
+	}
+	return result
+}
+
+func (world *World) checkNPC(npc *NPC) error {
+	if npc.Name == "" {
+		return fmt.Errorf("npc without name")
+	}
+	if _, ok := npc.Lines[npc.Start]; !ok && (npc.Start != "" || len(npc.Lines) > 0) {
+		return fmt.Errorf("unknown start line %q", npc.Start)
+	}
+	if err := checkStats(npc.Stats); err != nil {
+		return err
+	}
+	if npc.Hostile && (npc.Stats.Health == 0 || npc.Stats.Strength == 0) {
+		return fmt.Errorf("hostile npc must have health and strength")
+	}
+	if slices.Contains(npc.Loot, "") {
+		return fmt.Errorf("empty loot item")
+	}
+	npc.HP = npc.Stats.Health
+	for id, line := range npc.Lines {
+		for _, choice := range line.Choices {
+			if _, ok := npc.Lines[choice.Next]; choice.Next != "" && !ok {
+				return fmt.Errorf("line %q: unknown next line %q", id, choice.Next)
+			}
+			if slices.Contains(choice.Give, "") || slices.Contains(choice.Take, "") {
+				return fmt.Errorf("line %q: empty item", id)
+			}
+			if err := world.checkCondition(choice.If); err != nil {
+				return fmt.Errorf("line %q: %s", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// NPCNames - персонажи в текущей комнате
+func (player *Player) NPCNames() []string {
+	names := make([]string, 0, len(player.CurrentRoom.NPCs))
+	for _, npc := range player.CurrentRoom.NPCs {
+		if !npc.defeated() {
+			names = append(names, npc.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,70 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const neighbourWorld = `{
+	"start": "лестница",
+	"rooms": [{
+		"name": "лестница",
+		"npcs": [{
+			"name": "сосед",
+			"start": "привет",
+			"lines": {
+				"привет": {"text": "здорово! соль есть?", "choices": [
+					{"text": "есть, держи", "take": ["соль"], "next": "спасибо"},
+					{"text": "нет", "next": "жаль"},
+					{"text": "пока"}
+				]},
+				"спасибо": {"text": "спасибо, вот тебе пирог", "choices": [
+					{"text": "спасибо!", "give": ["пирог"], "set_flag": "помог соседу"}
+				]},
+				"жаль": {"text": "жаль"}
+			}
+		}]
+	}],
+	"items": [{"name": "сумка", "container": {"name_in_case": "в сумке"}}],
+	"quests": [{"name": "соседи", "room": "лестница", "steps": [
+		{"text": "помочь соседу", "done": {"flag": "помог соседу"}}
+	]}]
+}`
+
+func TestDialogue(t *testing.T) {
+	world, err := Load(strings.NewReader(neighbourWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.Equipment[DefaultSlot] = "сумка"
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{player.LookAround, "здесь: сосед, надо помочь соседу. можно пройти - "},
+		{func() string { return player.Answer("1") }, "вы ни с кем не разговариваете"},
+		{func() string { return player.Talk("кот") }, "здесь нет кот"},
+		{func() string { return player.Talk("сосед") }, "сосед: здорово! соль есть?\n1. нет\n2. пока"},
+		{func() string { return player.Answer("3") }, "нет такого варианта ответа"},
+		{func() string { return player.Answer("1") }, "сосед: жаль"},
+		{func() string { return player.Answer("1") }, "вы ни с кем не разговариваете"},
+		{func() string {
+			player.Inventory = append(player.Inventory, "соль")
+			return player.Talk("сосед")
+		},
+			"сосед: здорово! соль есть?\n1. есть, держи\n2. нет\n3. пока"},
+		{func() string { return player.Answer("1") }, "сосед: спасибо, вот тебе пирог\n1. спасибо!"},
+		{func() string { return player.Answer("1") }, "разговор окончен"},
+		{func() string { player.UpdateQuests(); return player.QuestsList() }, "соседи: выполнено"},
+	}
+	for i, c := range cases {
+		if answer := c.do(); answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %q\n\texpected: %q", i, answer, c.answer)
+		}
+	}
+	if !slices.Equal(player.Inventory, []Item{"пирог"}) {
+		t.Errorf("unexpected inventory %v", player.Inventory)
+	}
+}
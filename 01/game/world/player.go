@@ -1,111 +1,94 @@
 package world
 
-import (
-	"slices"
-	"strings"
-)
+import "slices"
 
 const cantUse = "не к чему применить"
 
 type Player struct {
+	Name        string
+	World       *World
 	CurrentRoom *Room
 	Inventory   []Item
-	HasBackpack bool
+	// надетые предметы по слотам, контейнеры среди них дают место для инвентаря
+	Equipment map[string]Item
+	// прогресс заданий по названиям и комнаты, где игрок побывал
+	Quests  map[string]QuestProgress
+	Visited map[string]bool
+	// отметки, которые ставят ответы в разговорах
+	Flags map[string]bool
+	// разговор, в котором игрок сейчас участвует
+	Dialogue *Dialogue
+	History  History
+	Health   int
+	// язык ответов, по умолчанию русский
+	Locale Locale
+	// как показывать описание комнаты, по умолчанию обычным текстом
+	Renderer Renderer
 }
 
-func (player *Player) GoToRoom(room *Room) (result string) {
-	if slices.Contains(player.CurrentRoom.NextRooms, room) {
-		if door := player.CurrentRoom.DoorFromRoom; door == nil || !slices.Contains(door.Rooms, room) || !door.IsClosed {
-			player.CurrentRoom = room
-			result = player.CurrentRoom.Note + ". " + player.CurrentRoom.NextRoomsList()
-		} else {
-			result = "дверь закрыта"
-		}
-	} else {
-		result = strings.Join([]string{"нет пути в", room.Name}, " ")
-	}
-	return
-}
-
-func (player *Player) LookAround() (result string) {
-	res := player.CurrentRoom.LookAroundNote
-	if res != "" {
-		res += ", "
-	}
-	emptyRoom := true
-	storages := player.CurrentRoom.Storages
-	var storageList []string
-	for _, storage := range storages {
-		if len(storage.Items) > 0 {
-			emptyRoom = false
-			var items []string
-			for _, item := range storage.Items {
-				items = append(items, string(item))
-			}
-			itemsList := strings.Join(items, ", ")
-			storageList = append(storageList, strings.Join([]string{storage.NameInCase, itemsList}, ": "))
-		}
-	}
-	res += strings.Join(storageList, ", ")
-	if emptyRoom {
-		res += "пустая комната"
+func (player *Player) LookAround() string {
+	renderer := player.Renderer
+	if renderer == nil {
+		renderer = TextRenderer{}
 	}
-	if len(player.CurrentRoom.Tasks) > 0 {
-		res = strings.Join([]string{res, player.CurrentRoom.TasksList()}, ", ")
+	if answer, blocked := player.blockedBy(player.CurrentRoom, OnLook, "", ""); blocked {
+		return answer
 	}
-	res = strings.Join([]string{res, player.CurrentRoom.NextRoomsList()}, ". ")
-	return res
+	says := player.runHooks(player.CurrentRoom, OnLook, "", "")
+	look := player.Look()
+	look.Notes = says
+	return renderer.RenderLook(player, look)
 }
 
 func (player *Player) TakeItem(item Item) (result string) {
-	storages := player.CurrentRoom.Storages
-	for _, storage := range storages {
-		for i, storageItem := range storage.Items {
-			if storageItem == item {
-				if !player.HasBackpack {
-					return "некуда класть"
-				}
-				result = strings.Join([]string{"предмет добавлен в инвентарь", string(item)}, ": ")
-				player.Inventory = append(player.Inventory, storageItem)
-				storage.Items = deleteItem(storage.Items, i)
-				return
-			}
-		}
+	storage, i, ok := player.CurrentRoom.findItem(item)
+	if !ok {
+		return player.T("нет такого")
 	}
-	return "нет такого"
-}
-
-func (player *Player) WearItem(item Item) (result string) {
-	storages := player.CurrentRoom.Storages
-	for _, storage := range storages {
-		for i, storageItem := range storage.Items {
-			if storageItem == item {
-				result = strings.Join([]string{"вы надели", string(item)}, ": ")
-				player.HasBackpack = true
-				storage.Items = deleteItem(storage.Items, i)
-				return
-			}
-		}
+	if refusal, ok := player.canCarry(item); !ok {
+		return refusal
+	}
+	if answer, blocked := player.blockedBy(player.CurrentRoom, OnTake, item, ""); blocked {
+		return answer
 	}
-	return "нет такого"
+	result = player.T("предмет добавлен в инвентарь: %s", item)
+	player.Inventory = append(player.Inventory, item)
+	storage.Items = deleteItem(storage.Items, i)
+	player.publish("взял %s", item)
+	return withHooks(result, player.runHooks(player.CurrentRoom, OnTake, item, ""))
 }
 
 func (player *Player) UseItem(item Item, target string) (result string) {
 	if !slices.Contains(player.Inventory, item) {
-		result = "нет предмета в инвентаре - " + string(item)
+		result = player.T("нет предмета в инвентаре - %s", item)
 		return
 	}
-	// В switch по типу предмета можно добавить другие цели для применения,
-	// если в игре добавятся используемые предметы кроме ключа
-	switch target {
-	case "дверь":
-		if player.CurrentRoom.DoorFromRoom != nil {
-			result = item.OpenDoor(player.CurrentRoom.DoorFromRoom)
-		} else {
-			result = cantUse
+	for _, interaction := range player.World.Interactions {
+		if interaction.matches(player, item, target) {
+			if answer, blocked := player.blockedBy(player.CurrentRoom, OnUse, item, target); blocked {
+				return answer
+			}
+			answer, dropped := interaction.apply(player, item, target)
+			result = player.T(answer) + player.droppedNote(dropped)
+			player.publish("применил %s: %s", item, result)
+			return withHooks(result, player.runHooks(player.CurrentRoom, OnUse, item, target))
 		}
-	default:
-		result = cantUse
 	}
-	return
+	return player.T(cantUse)
+}
+
+func (player *Player) Say(text string) string {
+	player.publish("говорит: %s", text)
+	return player.T("вы сказали: %s", text)
+}
+
+// Leave - игрок уходит из общего мира: всё, что у него было с собой и на нём,
+// остаётся на полу, чтобы вещи не пропали для остальных игроков
+func (player *Player) Leave() {
+	floor := player.CurrentRoom.Floor()
+	floor.Items = append(floor.Items, player.Inventory...)
+	floor.Items = append(floor.Items, player.Worn()...)
+	player.Inventory = player.Inventory[:0]
+	clear(player.Equipment)
+	player.publish("ушёл из игры")
 }
@@ -0,0 +1,125 @@
+package world
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Quest - цепочка шагов, которые выполняются строго по порядку
+type Quest struct {
+	Name string `json:"name"`
+	// в этой комнате невыполненные шаги показываются как "надо ..."
+	Room string `json:"room,omitempty"`
+	// задание становится доступным после выполнения другого задания
+	After  string       `json:"after,omitempty"`
+	Steps  []*Objective `json:"steps"`
+	Reward Reward       `json:"reward"`
+}
+
+type Objective struct {
+	Text string    `json:"text"`
+	Done Condition `json:"done"`
+}
+
+// Reward выдаётся один раз, когда выполнен последний шаг задания
+type Reward struct {
+	Text  string `json:"text,omitempty"`
+	Items []Item `json:"items,omitempty"`
+}
+
+// QuestProgress - сколько шагов задания игрок уже выполнил
+type QuestProgress struct {
+	Step int `json:"step"`
+}
+
+func (quest *Quest) done(player *Player) bool {
+	return player.Quests[quest.Name].Step >= len(quest.Steps)
+}
+
+func (quest *Quest) available(player *Player) bool {
+	if quest.After == "" {
+		return true
+	}
+	after := player.World.quest(quest.After)
+	return after != nil && after.done(player)
+}
+
+func (world *World) quest(name string) *Quest {
+	for _, quest := range world.Quests {
+		if quest.Name == name {
+			return quest
+		}
+	}
+	return nil
+}
+
+// UpdateQuests засчитывает выполненные шаги и выдаёт награды.
+// Выполненный шаг остаётся выполненным, даже если условие перестало выполняться
+func (player *Player) UpdateQuests() {
+	for changed := true; changed; {
+		changed = false
+		for _, quest := range player.World.Quests {
+			if quest.done(player) || !quest.available(player) {
+				continue
+			}
+			progress := player.Quests[quest.Name]
+			step := progress.Step
+			for progress.Step < len(quest.Steps) && quest.Steps[progress.Step].Done.Check(player) {
+				progress.Step++
+			}
+			// без изменений прогресс не записывается, иначе любая команда меняла бы состояние
+			// и попадала в журнал действий
+			if progress.Step == step {
+				continue
+			}
+			changed = true
+			player.Quests[quest.Name] = progress
+			if quest.done(player) {
+				player.reward(quest)
+			}
+		}
+	}
+}
+
+func (player *Player) reward(quest *Quest) {
+	dropped := player.receive(quest.Reward.Items)
+	text := player.T("задание выполнено: %s", Name(quest.Name))
+	if quest.Reward.Text != "" {
+		text += ". " + player.T(quest.Reward.Text)
+	}
+	if len(quest.Reward.Items) > 0 {
+		text += ". " + player.T("награда: %s", join(player, quest.Reward.Items)) + player.droppedNote(dropped)
+	}
+	player.World.Events.Tell(player, text)
+}
+
+// pendingTasks - невыполненные шаги доступных заданий, привязанных к комнате
+func (player *Player) pendingTasks(room *Room) []string {
+	var tasks []string
+	for _, quest := range player.World.Quests {
+		if quest.Room != room.Name || !quest.available(player) {
+			continue
+		}
+		for _, step := range quest.Steps[player.Quests[quest.Name].Step:] {
+			tasks = append(tasks, step.Text)
+		}
+	}
+	return tasks
+}
+
+func (player *Player) QuestsList() string {
+	var lines []string
+	for _, quest := range player.World.Quests {
+		switch {
+		case quest.done(player):
+			lines = append(lines, player.T("%s: выполнено", Name(quest.Name)))
+		case quest.available(player):
+			step := player.Quests[quest.Name].Step
+			lines = append(lines, fmt.Sprintf("%s: %s (%d/%d)", player.T(quest.Name), player.T(quest.Steps[step].Text), step+1, len(quest.Steps)))
+		}
+	}
+	if len(lines) == 0 {
+		return player.T("заданий нет")
+	}
+	return strings.Join(lines, "\n")
+}
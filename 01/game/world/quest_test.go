@@ -0,0 +1,70 @@
+package world
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const questWorld = `{
+	"start": "прихожая",
+	"rooms": [
+		{"name": "прихожая", "storages": [{"name_in_case": "на тумбе", "items": ["ключ", "сумка"]}],
+			"exits": [{"to": "двор", "door": "входная"}]},
+		{"name": "двор", "exits": [{"to": "прихожая", "door": "входная"}]}
+	],
+	"doors": [{"id": "входная", "closed": true, "closed_note": "закрыто", "open_note": "открыто"}],
+	"items": [{"name": "ключ", "properties": {"ключ": ""}}, {"name": "сумка", "container": {"name_in_case": "в сумке"}}],
+	"interactions": [{"item_property": "ключ", "target": "дверь", "toggle_door": true}],
+	"quests": [
+		{"name": "выйти", "room": "прихожая", "steps": [
+			{"text": "найти ключ", "done": {"has_items": ["ключ"]}},
+			{"text": "открыть дверь", "done": {"door_open": "входная"}}
+		]},
+		{"name": "погулять", "room": "прихожая", "after": "выйти", "steps": [
+			{"text": "побывать во дворе", "done": {"visited": "двор"}}
+		], "reward": {"text": "свежий воздух", "items": ["листик"]}}
+	]
+}`
+
+func TestQuestChain(t *testing.T) {
+	world, err := Load(strings.NewReader(questWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	var notes []string
+	world.Events.Subscribe(player, func(text string) {
+		notes = append(notes, text)
+	})
+	cases := []struct {
+		do     func() string
+		answer string
+	}{
+		{player.QuestsList, "выйти: найти ключ (1/2)"},
+		{func() string { return player.WearItem("сумка") }, "вы надели: сумка"},
+		{func() string { return player.TakeItem("ключ") }, "предмет добавлен в инвентарь: ключ"},
+		{func() string { return player.CurrentRoom.TasksList(player) }, "надо открыть дверь"},
+		{func() string { return player.UseItem("ключ", "дверь") }, "открыто"},
+		{player.QuestsList, "выйти: выполнено\nпогулять: побывать во дворе (1/1)"},
+		{func() string { return player.UseItem("ключ", "дверь") }, "закрыто"},
+		{func() string { return player.CurrentRoom.TasksList(player) }, "надо побывать во дворе"},
+		{func() string { return player.UseItem("ключ", "дверь") }, "открыто"},
+		{func() string { return player.Go("двор") }, ". можно пройти - прихожая"},
+		{player.QuestsList, "выйти: выполнено\nпогулять: выполнено"},
+	}
+	for i, c := range cases {
+		answer := c.do()
+		player.UpdateQuests()
+		if answer != c.answer {
+			t.Errorf("[%d] unexpected answer\n\tresult:   %s\n\texpected: %s", i, answer, c.answer)
+		}
+	}
+	expected := []string{"задание выполнено: выйти", "задание выполнено: погулять. свежий воздух. награда: листик"}
+	if !slices.Equal(notes, expected) {
+		t.Errorf("unexpected notes %q, expected %q", notes, expected)
+	}
+	if !slices.Contains(player.Inventory, "листик") {
+		t.Errorf("reward is not in inventory: %v", player.Inventory)
+	}
+}
@@ -0,0 +1,127 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Renderer собирает из описания комнаты ответ на команду осмотреться
+type Renderer interface {
+	RenderLook(player *Player, look Look) string
+}
+
+// Renderers - способы вывода по названиям, игрок выбирает свой командой или флагом
+var Renderers = map[string]Renderer{
+	"text": TextRenderer{},
+	"ansi": ANSIRenderer{},
+	"json": JSONRenderer{},
+}
+
+// TextRenderer - обычный текст одной строкой
+type TextRenderer struct{}
+
+func (TextRenderer) RenderLook(player *Player, look Look) string {
+	return renderText(player, look, plain)
+}
+
+func plain(_ style, text string) string {
+	return text
+}
+
+// ANSIRenderer - тот же текст, раскрашенный для терминала
+type ANSIRenderer struct{}
+
+type style string
+
+const (
+	styleStorage style = "\x1b[1m"
+	styleClosed  style = "\x1b[2m"
+	styleItem    style = "\x1b[33m"
+	styleNPC     style = "\x1b[35m"
+	styleTask    style = "\x1b[32m"
+	styleExit    style = "\x1b[36m"
+	styleReset         = "\x1b[0m"
+)
+
+func (ANSIRenderer) RenderLook(player *Player, look Look) string {
+	return renderText(player, look, func(s style, text string) string { return string(s) + text + styleReset })
+}
+
+// JSONRenderer - описание как есть, для программ
+type JSONRenderer struct{}
+
+func (JSONRenderer) RenderLook(player *Player, look Look) string {
+	data, err := json.Marshal(look)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// renderText собирает текстовое описание, paint оформляет отдельные части.
+// Тексты в look уже переведены, переводятся только шаблоны
+func renderText(player *Player, look Look, paint func(s style, text string) string) string {
+	format := func(template string, args ...any) string {
+		return fmt.Sprintf(player.translate(template), args...)
+	}
+	res := look.Description
+	if res != "" {
+		res += ", "
+	}
+	var parts []string
+	for _, storage := range look.Storages {
+		if storage.Closed {
+			parts = append(parts, paint(styleClosed, format("%s закрыт", storage.Name)))
+			continue
+		}
+		parts = append(parts, paint(styleStorage, storage.Place)+": "+renderItems(storage.Items, paint))
+	}
+	if len(look.NPCs) > 0 {
+		npcs := make([]string, 0, len(look.NPCs))
+		for _, npc := range look.NPCs {
+			npcs = append(npcs, paint(styleNPC, npc))
+		}
+		parts = append(parts, format("здесь: %s", strings.Join(npcs, ", ")))
+	}
+	res += strings.Join(parts, ", ")
+	if len(parts) == 0 {
+		res += player.translate("пустая комната")
+	}
+	if len(look.Tasks) > 0 {
+		tasks := make([]string, 0, len(look.Tasks))
+		for _, task := range look.Tasks {
+			tasks = append(tasks, paint(styleTask, task))
+		}
+		res += ", " + format("надо %s", strings.Join(tasks, player.translate(" и ")))
+	}
+	exits := make([]string, 0, len(look.Exits))
+	for _, exit := range look.Exits {
+		exits = append(exits, paint(styleExit, exit.Name))
+	}
+	res += ". " + format("можно пройти - %s", strings.Join(exits, ", "))
+	return withHooks(res, look.Notes)
+}
+
+// renderItems перечисляет предметы, содержимое контейнеров в скобках
+func renderItems(items []ItemView, paint func(s style, text string) string) string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		name := paint(styleItem, item.Name)
+		if len(item.Items) > 0 {
+			name += " (" + renderItems(item.Items, paint) + ")"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// SetRenderer выбирает способ вывода описания комнаты
+func (player *Player) SetRenderer(name string) string {
+	renderer, ok := Renderers[name]
+	if !ok {
+		return player.T("нет такого формата: %s", name)
+	}
+	player.Renderer = renderer
+	return player.T("формат: %s", name)
+}
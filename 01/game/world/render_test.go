@@ -0,0 +1,48 @@
+package world
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRenderers(t *testing.T) {
+	world, err := Load(strings.NewReader(questWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	text := player.LookAround()
+	if text != "на тумбе: ключ, сумка, надо найти ключ и открыть дверь. можно пройти - двор" {
+		t.Errorf("text: %s", text)
+	}
+
+	if answer := player.SetRenderer("ansi"); answer != "формат: ansi" {
+		t.Errorf("set renderer: %s", answer)
+	}
+	colored := player.LookAround()
+	if !strings.Contains(colored, styleReset) {
+		t.Errorf("ansi output has no colors: %q", colored)
+	}
+	// без кодов цвета получается тот же текст
+	for _, s := range []string{string(styleStorage), string(styleClosed), string(styleItem), string(styleNPC), string(styleTask), string(styleExit), styleReset} {
+		colored = strings.ReplaceAll(colored, s, "")
+	}
+	if colored != text {
+		t.Errorf("ansi without colors: %s", colored)
+	}
+
+	player.SetRenderer("json")
+	var look Look
+	if err := json.Unmarshal([]byte(player.LookAround()), &look); err != nil {
+		t.Fatal(err)
+	}
+	if look.Room != "прихожая" || len(look.Storages) != 1 || len(look.Storages[0].Items) != 2 ||
+		len(look.Tasks) != 2 || look.Exits[0].Door != "дверь" || !look.Exits[0].Closed {
+		t.Errorf("json look: %+v", look)
+	}
+
+	if answer := player.SetRenderer("html"); answer != "нет такого формата: html" {
+		t.Errorf("unknown renderer: %s", answer)
+	}
+}
@@ -1,49 +1,67 @@
 package world
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 type Room struct {
 	Name           string
 	Storages       []*Storage
-	NextRooms      []*Room
-	DoorFromRoom   *Door
+	Exits          []*Exit
 	Note           string
-	Tasks          []Task
 	LookAroundNote string
+	// подробное описание для команды осмотреть без аргументов
+	Description string
+	// команды, доступные только в этой комнате
+	Commands Commands
+	NPCs     []*NPC
+	// события по расписанию, запускаются World.Tick
+	Events []*TimedEvent
+	// реакции комнаты на действия игроков
+	Hooks []*Hook
 }
 
-func (room *Room) NextRoomsList() (result string) {
-	result = "можно пройти - "
-	nextRoomsNames := make([]string, 0, len(room.NextRooms))
-	for _, nextRoom := range room.NextRooms {
-		nextRoomsNames = append(nextRoomsNames, nextRoom.Name)
+func (room *Room) NextRoomsList(player *Player) string {
+	nextRoomsNames := make([]string, 0, len(room.Exits))
+	for _, exit := range room.Exits {
+		nextRoomsNames = append(nextRoomsNames, exit.Name)
 	}
-	result += strings.Join(nextRoomsNames, ", ")
-	return
+	return player.T("можно пройти - %s", join(player, nextRoomsNames))
 }
 
-func (room *Room) TasksList() (result string) {
-	result = "надо "
-	var tasks []string
-	for _, task := range room.Tasks {
-		if !task.Done() {
-			tasks = append(tasks, task.Text)
-		}
+func (room *Room) TasksList(player *Player) string {
+	pending := player.pendingTasks(room)
+	tasks := make([]string, 0, len(pending))
+	for _, task := range pending {
+		tasks = append(tasks, player.T(task))
 	}
-	result += strings.Join(tasks, " и ")
-	return
+	return player.T("надо %s", strings.Join(tasks, player.T(" и ")))
 }
 
-func (item Item) OpenDoor(door *Door) string {
-	if item != "ключи" {
-		return cantUse
+// FloorName - хранилище, которое загрузчик добавляет в каждую комнату для выброшенных предметов
+const FloorName = "пол"
+
+func (room *Room) Floor() *Storage {
+	for _, storage := range room.Storages {
+		if storage.Name == FloorName {
+			return storage
+		}
 	}
-	door.IsClosed = !door.IsClosed
-	return door.States[door.IsClosed]
+	floor := &Storage{Name: FloorName, NameInCase: "на полу"}
+	room.Storages = append(room.Storages, floor)
+	return floor
 }
 
-type Door struct {
-	IsClosed bool
-	States   map[bool]string
-	Rooms    []*Room
+// findItem ищет предмет на виду, в закрытые хранилища не заглядывает
+func (room *Room) findItem(item Item) (storage *Storage, index int, ok bool) {
+	for _, storage = range room.Storages {
+		if storage.Closed {
+			continue
+		}
+		if index = slices.Index(storage.Items, item); index >= 0 {
+			return storage, index, true
+		}
+	}
+	return nil, 0, false
 }
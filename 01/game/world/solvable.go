@@ -0,0 +1,67 @@
+package world
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CheckSolvable проверяет, что из стартовой комнаты можно дойти до комнаты goal.
+// Игрок собирает всё, до чего может добраться, и открывает двери и хранилища найденными ключами,
+// пока находится что-то новое. Проходы с условиями и предметы из разговоров и наград
+// не учитываются, так что проверка может счесть проходимый мир непроходимым, но не наоборот
+func (world *World) CheckSolvable(goal string) error {
+	target, ok := world.Rooms[goal]
+	if !ok {
+		return fmt.Errorf("unknown goal room %q", goal)
+	}
+	if reached, _ := world.explore(true); !reached[target] {
+		return fmt.Errorf("goal room %q is unreachable from %q", goal, world.Start.Name)
+	}
+	return nil
+}
+
+// explore возвращает комнаты, до которых можно дойти, и предметы, которые можно в них найти.
+// strict - проходы с условиями закрыты, иначе условия считаются выполнимыми
+func (world *World) explore(strict bool) (reached map[*Room]bool, items map[Item]bool) {
+	reached = map[*Room]bool{world.Start: true}
+	items = make(map[Item]bool)
+	for changed := true; changed; {
+		changed = false
+		for room := range reached {
+			for _, storage := range room.Storages {
+				if storage.Locked && !items[storage.Key] {
+					continue
+				}
+				changed = world.collect(storage.Items, items) || changed
+				changed = world.collect(storage.Hidden, items) || changed
+			}
+			for _, exit := range room.Exits {
+				if exit.To == nil || reached[exit.To] || strict && !reflect.DeepEqual(exit.If, Condition{}) {
+					continue
+				}
+				if exit.Door != nil && exit.Door.Locked && !items[exit.Door.Key] {
+					continue
+				}
+				reached[exit.To] = true
+				changed = true
+			}
+		}
+	}
+	return reached, items
+}
+
+// collect добавляет предметы вместе с содержимым контейнеров, возвращает, нашлось ли новое
+func (world *World) collect(items []Item, found map[Item]bool) (changed bool) {
+	for _, item := range items {
+		if !found[item] {
+			found[item] = true
+			changed = true
+		}
+		// запертый контейнер мог открыться ключом, найденным позже него самого
+		if container, ok := world.Containers[item]; ok && (!container.Locked || found[container.Key]) {
+			changed = world.collect(container.Items, found) || changed
+			changed = world.collect(container.Hidden, found) || changed
+		}
+	}
+	return changed
+}
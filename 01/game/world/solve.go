@@ -0,0 +1,511 @@
+package world
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SolveLimit - сколько разных состояний мира решатель перебирает, прежде чем сдаться
+const SolveLimit = 200000
+
+// move - ход решателя: команда, как её набрал бы игрок, и её выполнение
+type move struct {
+	command string
+	run     func(player *Player) string
+}
+
+// solveNode - ход, которым решатель дошёл до состояния. Само состояние не хранится:
+// их сотни тысяч, и каждое описывает весь мир. Оно восстанавливается повтором
+// ходов от начала игры, см. solver.replay
+type solveNode struct {
+	parent *solveNode
+	move   move
+}
+
+// solver - поиск решения на копии мира, чтобы не трогать игроков настоящего
+type solver struct {
+	world  *World
+	player *Player
+	// предметы, которые есть смысл надевать и подбирать, см. wearable и needed
+	wearable map[Item]bool
+	needed   map[Item]bool
+	// комнаты, посещение которых проверяют условия: остальные посещения состояние не различает
+	visited map[string]bool
+	// порядок комнат и контейнеров в ключе состояния
+	rooms      []*Room
+	containers []Item
+	start      *State
+}
+
+// Solve поиском в ширину по состояниям мира ищет кратчайшую последовательность команд,
+// после которой выполнены все задания. Ходы выполняются настоящими методами Player
+// на копии мира без подписчиков, так что решатель видит мир так же, как игрок,
+// а сам мир и игроки в нём поиска не замечают. Драки зависят от случайности, поэтому
+// атаковать решатель не пробует
+func (world *World) Solve() ([]string, error) {
+	search := world.clone()
+	s := &solver{
+		world:    search,
+		player:   search.NewPlayer(),
+		wearable: search.wearable(),
+		needed:   search.needed(),
+		visited:  make(map[string]bool),
+	}
+	for _, cond := range search.conditions() {
+		s.visited[cond.Visited] = true
+	}
+	for _, room := range search.Rooms {
+		s.rooms = append(s.rooms, room)
+	}
+	slices.SortFunc(s.rooms, func(a, b *Room) int { return strings.Compare(a.Name, b.Name) })
+	for item := range search.Containers {
+		s.containers = append(s.containers, item)
+	}
+	slices.Sort(s.containers)
+
+	player := s.player
+	s.start = search.SaveState(player)
+	seen := map[[16]byte]bool{s.key(): true}
+	queue := []*solveNode{{}}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if err := s.replay(node); err != nil {
+			return nil, err
+		}
+		if player.questsDone() {
+			return node.path(), nil
+		}
+		// ходы пробуются из одного состояния, его достаточно запомнить на время перебора ходов
+		state, dialogue := search.SaveState(player), player.Dialogue
+		for _, move := range s.moves() {
+			if err := s.restore(state, dialogue); err != nil {
+				return nil, err
+			}
+			s.step(move)
+			key := s.key()
+			if seen[key] {
+				continue
+			}
+			if len(seen) >= SolveLimit {
+				return nil, fmt.Errorf("no solution within %d states", SolveLimit)
+			}
+			seen[key] = true
+			queue = append(queue, &solveNode{parent: node, move: move})
+		}
+	}
+	return nil, fmt.Errorf("world can't be solved: %d states checked", len(seen))
+}
+
+// step делает ход так же, как игровой цикл выполняет команду
+func (s *solver) step(move move) {
+	move.run(s.player)
+	s.world.Tick(s.world.Clock.PerCommand)
+	s.player.UpdateQuests()
+}
+
+func (s *solver) restore(state *State, dialogue *Dialogue) error {
+	if err := s.world.RestoreState(s.player, state); err != nil {
+		return err
+	}
+	if dialogue != nil {
+		s.player.Dialogue = &Dialogue{NPC: dialogue.NPC, Line: dialogue.Line}
+	}
+	return nil
+}
+
+// replay восстанавливает состояние узла: начало игры и все ходы до него.
+// Ходы не случайны, поэтому повтор всегда приводит к тому же состоянию
+func (s *solver) replay(node *solveNode) error {
+	var moves []move
+	for ; node.parent != nil; node = node.parent {
+		moves = append(moves, node.move)
+	}
+	if err := s.restore(s.start, nil); err != nil {
+		return err
+	}
+	for i := len(moves) - 1; i >= 0; i-- {
+		s.step(moves[i])
+	}
+	return nil
+}
+
+func (node *solveNode) path() []string {
+	var commands []string
+	for ; node.parent != nil; node = node.parent {
+		commands = append(commands, node.move.command)
+	}
+	slices.Reverse(commands)
+	return commands
+}
+
+// key - хеш текущего состояния без учёта времени, бросков и порядка предметов в инвентаре:
+// иначе каждый ход давал бы новое состояние и перебор никогда бы не заканчивался.
+// Совпадение 128-битных хешей разных состояний на сотнях тысяч состояний практически невозможно
+func (s *solver) key() [16]byte {
+	var key strings.Builder
+	field := func(value string) {
+		key.WriteString(value)
+		key.WriteByte('|')
+	}
+	items := func(items []Item) {
+		for _, item := range items {
+			key.WriteString(string(item))
+			key.WriteByte(',')
+		}
+		key.WriteByte('|')
+	}
+	flag := func(on bool) {
+		if on {
+			key.WriteByte('1')
+		} else {
+			key.WriteByte('0')
+		}
+	}
+	storage := func(storage *Storage) {
+		items(storage.Items)
+		flag(storage.Closed)
+		flag(storage.Locked)
+		field(strconv.Itoa(len(storage.Hidden)))
+	}
+
+	player := s.player
+	field(player.CurrentRoom.Name)
+	field(strconv.Itoa(player.Health))
+	inventory := slices.Clone(player.Inventory)
+	slices.Sort(inventory)
+	items(inventory)
+	items(player.Worn())
+	for _, quest := range s.world.Quests {
+		field(strconv.Itoa(player.Quests[quest.Name].Step))
+	}
+	flags := make([]string, 0, len(player.Flags))
+	for name := range player.Flags {
+		flags = append(flags, name)
+	}
+	slices.Sort(flags)
+	field(strings.Join(flags, ","))
+	if player.Dialogue != nil {
+		field(player.Dialogue.NPC.Name + "/" + player.Dialogue.Line)
+	}
+	for _, room := range s.rooms {
+		if s.visited[room.Name] {
+			flag(player.Visited[room.Name])
+		}
+		field(room.Note)
+		field(room.LookAroundNote)
+		for _, roomStorage := range room.Storages {
+			storage(roomStorage)
+		}
+		for _, npc := range room.NPCs {
+			field(strconv.Itoa(npc.HP))
+		}
+		for _, hook := range room.Hooks {
+			flag(hook.fired)
+		}
+	}
+	for _, door := range s.world.Doors {
+		flag(door.IsClosed)
+		flag(door.Locked)
+	}
+	for _, item := range s.containers {
+		storage(s.world.Containers[item])
+	}
+	var sum [16]byte
+	hash := fnv.New128a()
+	hash.Write([]byte(key.String()))
+	hash.Sum(sum[:0])
+	return sum
+}
+
+// clone - копия мира для решателя: комнаты, двери, хранилища, персонажи и обработчики
+// свои, неизменяемые описания общие с оригиналом, подписчиков у копии нет
+func (world *World) clone() *World {
+	clone := &World{
+		Rooms:        make(map[string]*Room, len(world.Rooms)),
+		Items:        world.Items,
+		Containers:   make(map[Item]*Storage, len(world.Containers)),
+		Interactions: world.Interactions,
+		Quests:       world.Quests,
+		Clock:        world.Clock,
+		PlayerStats:  world.PlayerStats,
+		Combat:       world.Combat,
+		Translations: world.Translations,
+	}
+	doors := make(map[*Door]*Door, len(world.Doors))
+	for _, door := range world.Doors {
+		copied := *door
+		doors[door] = &copied
+		clone.Doors = append(clone.Doors, &copied)
+	}
+	for name, room := range world.Rooms {
+		copied := *room
+		copied.Storages = make([]*Storage, 0, len(room.Storages))
+		for _, storage := range room.Storages {
+			copied.Storages = append(copied.Storages, storage.Clone())
+		}
+		copied.NPCs = make([]*NPC, 0, len(room.NPCs))
+		for _, npc := range room.NPCs {
+			npcCopy := *npc
+			copied.NPCs = append(copied.NPCs, &npcCopy)
+		}
+		copied.Hooks = make([]*Hook, 0, len(room.Hooks))
+		for _, hook := range room.Hooks {
+			hookCopy := *hook
+			copied.Hooks = append(copied.Hooks, &hookCopy)
+		}
+		clone.Rooms[name] = &copied
+	}
+	for _, room := range clone.Rooms {
+		exits := make([]*Exit, 0, len(room.Exits))
+		for _, exit := range room.Exits {
+			copied := *exit
+			copied.To = clone.Rooms[exit.To.Name]
+			if exit.Door != nil {
+				copied.Door = doors[exit.Door]
+			}
+			exits = append(exits, &copied)
+		}
+		room.Exits = exits
+	}
+	for item, container := range world.Containers {
+		clone.Containers[item] = container.Clone()
+	}
+	clone.Start = clone.Rooms[world.Start.Name]
+	if world.Combat.Respawn != nil {
+		clone.Combat.Respawn = clone.Rooms[world.Combat.Respawn.Name]
+	}
+	return clone
+}
+
+// conditions - все условия мира: заданий, применений, проходов, обработчиков и разговоров
+func (world *World) conditions() []Condition {
+	var conditions []Condition
+	for _, quest := range world.Quests {
+		for _, step := range quest.Steps {
+			conditions = append(conditions, step.Done)
+		}
+	}
+	for _, interaction := range world.Interactions {
+		conditions = append(conditions, interaction.If)
+	}
+	for _, room := range world.Rooms {
+		for _, exit := range room.Exits {
+			conditions = append(conditions, exit.If)
+		}
+		for _, hook := range room.Hooks {
+			conditions = append(conditions, hook.If)
+		}
+		for _, npc := range room.NPCs {
+			for _, line := range npc.Lines {
+				for _, choice := range line.Choices {
+					conditions = append(conditions, choice.If)
+				}
+			}
+		}
+	}
+	return conditions
+}
+
+// wearable - предметы, которые есть смысл надевать: контейнеры, вещи с эффектами
+// и то, что надетым требуют условия мира
+func (world *World) wearable() map[Item]bool {
+	wearable := make(map[Item]bool)
+	for name, itemType := range world.Items {
+		if itemType.Slot != "" && (itemType.Container != nil || itemType.Armor > 0 || itemType.Warmth > 0 || itemType.Light) {
+			wearable[name] = true
+		}
+	}
+	for _, cond := range world.conditions() {
+		for _, item := range cond.Wears {
+			wearable[item] = true
+		}
+	}
+	return wearable
+}
+
+// needed - предметы, которые есть смысл подбирать: ключи, то, что применяется
+// или применяется к чему-то, что требуют условия, обработчики и собеседники.
+// Остальное решатель не берёт, иначе число состояний растёт с каждым предметом мира
+func (world *World) needed() map[Item]bool {
+	needed := make(map[Item]bool)
+	for _, door := range world.Doors {
+		needed[door.Key] = true
+	}
+	for _, container := range world.Containers {
+		needed[container.Key] = true
+	}
+	for _, interaction := range world.Interactions {
+		needed[interaction.Item] = true
+		needed[Item(interaction.Target)] = true
+		if interaction.ItemProperty != "" {
+			for name := range world.Items {
+				if world.HasProperty(name, interaction.ItemProperty) {
+					needed[name] = true
+				}
+			}
+		}
+	}
+	for _, cond := range world.conditions() {
+		for _, item := range cond.HasItems {
+			needed[item] = true
+		}
+	}
+	for _, room := range world.Rooms {
+		for _, storage := range room.Storages {
+			needed[storage.Key] = true
+		}
+		for _, hook := range room.Hooks {
+			needed[hook.Item] = true
+		}
+		for _, npc := range room.NPCs {
+			for _, line := range npc.Lines {
+				for _, choice := range line.Choices {
+					for _, item := range choice.Take {
+						needed[item] = true
+					}
+				}
+			}
+		}
+	}
+	delete(needed, "")
+	return needed
+}
+
+func (player *Player) questsDone() bool {
+	for _, quest := range player.World.Quests {
+		if !quest.done(player) {
+			return false
+		}
+	}
+	return true
+}
+
+// moves - ходы, которые имеет смысл попробовать в текущем состоянии. Бесполезные
+// ходы тоже попадают сюда, но ничего не меняют и отсеиваются как уже виденные состояния.
+// Подбирать решатель пробует только нужные предметы. Выбрасывать, перекладывать
+// и снимать - только когда нужное не помещается или его некуда надеть: иначе
+// каждый предмет можно было бы оставить в любой комнате
+func (s *solver) moves() []move {
+	player := s.player
+	var moves []move
+	add := func(command string, run func(player *Player) string) {
+		moves = append(moves, move{command: command, run: run})
+	}
+	if player.Dialogue != nil {
+		for i := range player.availableChoices() {
+			number := strconv.Itoa(i + 1)
+			add("ответить "+number, func(player *Player) string { return player.Answer(number) })
+		}
+	}
+	room := player.CurrentRoom
+	for _, exit := range room.Exits {
+		add("идти "+exit.Name, func(player *Player) string { return player.Go(exit.Name) })
+	}
+	// нужный предмет не помещается в инвентарь или занят слот для того, что есть смысл надеть
+	full, slotTaken := false, false
+	consider := func(item Item) {
+		if s.needed[item] {
+			_, ok := player.canCarry(item)
+			full = full || !ok && player.HasBackpack()
+		}
+		if s.wearable[item] {
+			_, ok := player.Equipment[player.World.slot(item)]
+			slotTaken = slotTaken || ok
+		}
+	}
+	var containers []string
+	for _, storage := range room.Storages {
+		if storage.Name != "" {
+			containers = append(containers, storage.Name)
+		}
+		if storage.Closed {
+			continue
+		}
+		for _, item := range storage.Items {
+			consider(item)
+			if s.needed[item] {
+				add("взять "+string(item), func(player *Player) string { return player.TakeItem(item) })
+			}
+			if s.wearable[item] {
+				add("надеть "+string(item), func(player *Player) string { return player.WearItem(item) })
+			}
+			if _, ok := player.World.Containers[item]; ok {
+				containers = append(containers, string(item))
+			}
+		}
+	}
+	for _, item := range player.Inventory {
+		if _, ok := player.World.Containers[item]; ok {
+			containers = append(containers, string(item))
+		}
+	}
+	for _, exit := range room.Exits {
+		if exit.Door != nil && !slices.Contains(containers, exit.Door.Name) {
+			containers = append(containers, exit.Door.Name)
+		}
+	}
+	var open []string
+	for _, name := range containers {
+		add("открыть "+name, func(player *Player) string { return player.OpenStorage(name) })
+		storage := player.findContainer(name)
+		if storage == nil || storage.Closed {
+			continue
+		}
+		if len(storage.Hidden) > 0 {
+			add("осмотреть "+name, func(player *Player) string { return player.Examine(name, "") })
+		}
+		if _, ok := player.World.Containers[Item(name)]; ok {
+			for _, item := range storage.Items {
+				consider(item)
+				if s.needed[item] {
+					add("взять "+string(item)+" "+name, func(player *Player) string { return player.TakeItemFrom(item, name) })
+				}
+			}
+		}
+		// положить на пол - то же, что выбросить
+		if name != FloorName {
+			open = append(open, name)
+		}
+	}
+	for _, name := range open {
+		if !full {
+			break
+		}
+		for _, item := range player.Inventory {
+			add("положить "+string(item)+" "+name, func(player *Player) string { return player.PutItem(item, name) })
+		}
+	}
+	var targets []string
+	for _, interaction := range player.World.Interactions {
+		if !slices.Contains(targets, interaction.Target) {
+			targets = append(targets, interaction.Target)
+		}
+	}
+	for _, item := range player.Inventory {
+		for _, target := range targets {
+			// открытую дверь решатель не закрывает: к цели это не приближает
+			if door := room.findDoor(target); door != nil && !door.IsClosed {
+				continue
+			}
+			add("применить "+string(item)+" "+target, func(player *Player) string { return player.UseItem(item, target) })
+		}
+		if full {
+			add("выбросить "+string(item), func(player *Player) string { return player.DropItem(item) })
+		}
+	}
+	for _, item := range player.Worn() {
+		if full || slotTaken {
+			add("снять "+string(item), func(player *Player) string { return player.TakeOff(item) })
+		}
+	}
+	for _, npc := range room.NPCs {
+		if len(npc.Lines) > 0 && !npc.defeated() {
+			add("поговорить "+npc.Name, func(player *Player) string { return player.Talk(npc.Name) })
+		}
+	}
+	return moves
+}
@@ -0,0 +1,91 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	world, err := Load(strings.NewReader(questWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// поиск идёт на копии мира, игроки настоящего его не видят
+	var heard []string
+	world.Events.Subscribe(world.NewPlayer(), func(text string) { heard = append(heard, text) })
+	commands, err := world.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heard) > 0 {
+		t.Errorf("solve published events: %v", heard)
+	}
+	expected := []string{
+		"надеть сумка",
+		"взять ключ",
+		"открыть дверь",
+		"идти двор",
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got solution:\n%s\nexpected:\n%s", strings.Join(commands, "\n"), strings.Join(expected, "\n"))
+	}
+	// поиск не должен оставлять следов в мире
+	player := world.NewPlayer()
+	if got := player.LookAround(); !strings.Contains(got, "на тумбе: ключ, сумка") {
+		t.Errorf("world changed after solve: %s", got)
+	}
+
+	generated, err := Generate(GenerateOptions{Seed: 1, Rooms: 6, LockedDoors: 2, Items: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = generated.Solve(); err != nil {
+		t.Errorf("generated dungeon: %s", err)
+	}
+
+	world, err = Load(strings.NewReader(`{
+		"start": "холл",
+		"rooms": [
+			{"name": "холл", "exits": [{"to": "сейф", "door": "стальная"}]},
+			{"name": "сейф", "storages": [{"name_in_case": "на полке", "items": ["ключ"]}],
+				"exits": [{"to": "холл", "door": "стальная"}]}
+		],
+		"doors": [{"id": "стальная", "closed": true, "locked": true, "key": "ключ"}],
+		"quests": [{"name": "ограбление", "steps": [{"text": "попасть в сейф", "done": {"in_room": "сейф"}}]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = world.Solve(); err == nil {
+		t.Error("expected unsolvable world")
+	}
+}
+
+func TestSolveFreesCapacity(t *testing.T) {
+	world, err := Load(strings.NewReader(`{
+		"start": "холл",
+		"rooms": [
+			{"name": "холл", "storages": [{"name_in_case": "на полу", "items": ["сумка", "ключ1", "ключ2"]}],
+				"exits": [{"to": "коридор", "door": "первая"}]},
+			{"name": "коридор", "exits": [{"to": "холл", "door": "первая"}, {"to": "выход", "door": "вторая"}]},
+			{"name": "выход", "exits": [{"to": "коридор", "door": "вторая"}]}
+		],
+		"doors": [
+			{"id": "первая", "name": "первая", "closed": true, "locked": true, "key": "ключ1"},
+			{"id": "вторая", "name": "вторая", "closed": true, "locked": true, "key": "ключ2"}
+		],
+		"items": [{"name": "сумка", "slot": "спина", "container": {"name_in_case": "в сумке", "capacity": 1}}],
+		"quests": [{"name": "выбраться", "steps": [{"text": "выйти", "done": {"in_room": "выход"}}]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands, err := world.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "надеть сумка, взять ключ1, открыть первая, выбросить ключ1, взять ключ2, идти коридор, открыть вторая, идти выход"
+	if got := strings.Join(commands, ", "); got != expected {
+		t.Errorf("unexpected solution: %s", got)
+	}
+}
@@ -0,0 +1,275 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// StateVersion меняется при любом несовместимом изменении формата сохранения
+const StateVersion = 12
+
+// State - изменяемая часть мира и состояние игрока. Сами комнаты и связи между ними
+// не сохраняются: сохранение восстанавливается поверх того же файла мира
+type State struct {
+	Version int                  `json:"version"`
+	Rooms   map[string]RoomState `json:"rooms"`
+	// в порядке World.Doors
+	Doors []DoorState `json:"doors"`
+	// содержимое предметов-контейнеров
+	Containers map[Item]StorageState `json:"containers"`
+	Player     PlayerState           `json:"player"`
+	// минут с начала игры
+	Minute int `json:"minute"`
+	// сколько случайных чисел выдано для драк: загрузка продолжает ту же последовательность
+	Draws int `json:"draws"`
+}
+
+type RoomState struct {
+	// описания меняются применением предметов
+	Note           string `json:"note"`
+	LookAroundNote string `json:"look_around_note"`
+	// в порядке Room.Storages
+	Storages []StorageState `json:"storages"`
+	// здоровье персонажей, которые участвуют в драках
+	NPCs map[string]int `json:"npcs,omitempty"`
+	// номера сработавших одноразовых обработчиков
+	FiredHooks []int `json:"fired_hooks,omitempty"`
+}
+
+type DoorState struct {
+	Closed bool `json:"closed,omitempty"`
+	Locked bool `json:"locked,omitempty"`
+}
+
+type StorageState struct {
+	Items  []Item `json:"items"`
+	Closed bool   `json:"closed,omitempty"`
+	Locked bool   `json:"locked,omitempty"`
+	// ещё не найденные спрятанные предметы
+	Hidden []Item `json:"hidden,omitempty"`
+}
+
+func saveStorage(storage *Storage) StorageState {
+	return StorageState{
+		Items:  slices.Clone(storage.Items),
+		Closed: storage.Closed,
+		Locked: storage.Locked,
+		Hidden: slices.Clone(storage.Hidden),
+	}
+}
+
+func (state StorageState) restore(storage *Storage) {
+	storage.Items = slices.Clone(state.Items)
+	storage.Closed = state.Closed
+	storage.Locked = state.Locked
+	storage.Hidden = slices.Clone(state.Hidden)
+}
+
+type PlayerState struct {
+	Room      string `json:"room"`
+	Inventory []Item `json:"inventory"`
+	Worn      []Item `json:"worn"`
+	// прогресс заданий по названиям
+	Quests  map[string]QuestProgress `json:"quests"`
+	Visited []string                 `json:"visited"`
+	Flags   []string                 `json:"flags"`
+	Health  int                      `json:"health"`
+}
+
+func (world *World) SaveState(player *Player) *State {
+	state := &State{
+		Version:    StateVersion,
+		Rooms:      make(map[string]RoomState, len(world.Rooms)),
+		Doors:      make([]DoorState, 0, len(world.Doors)),
+		Containers: make(map[Item]StorageState, len(world.Containers)),
+		Minute:     world.Clock.Minute,
+		Draws:      world.draws(),
+		Player: PlayerState{
+			Room:      player.CurrentRoom.Name,
+			Inventory: slices.Clone(player.Inventory),
+			Worn:      player.Worn(),
+			Quests:    maps.Clone(player.Quests),
+			Visited:   make([]string, 0, len(player.Visited)),
+			Health:    player.Health,
+		},
+	}
+	for name, room := range world.Rooms {
+		roomState := RoomState{
+			Note:           room.Note,
+			LookAroundNote: room.LookAroundNote,
+			Storages:       make([]StorageState, 0, len(room.Storages)),
+		}
+		for _, storage := range room.Storages {
+			roomState.Storages = append(roomState.Storages, saveStorage(storage))
+		}
+		for _, npc := range room.NPCs {
+			if npc.Stats.Health > 0 {
+				if roomState.NPCs == nil {
+					roomState.NPCs = make(map[string]int)
+				}
+				roomState.NPCs[npc.Name] = npc.HP
+			}
+		}
+		for i, hook := range room.Hooks {
+			if hook.fired {
+				roomState.FiredHooks = append(roomState.FiredHooks, i)
+			}
+		}
+		state.Rooms[name] = roomState
+	}
+	for name := range player.Visited {
+		state.Player.Visited = append(state.Player.Visited, name)
+	}
+	slices.Sort(state.Player.Visited)
+	for flag := range player.Flags {
+		state.Player.Flags = append(state.Player.Flags, flag)
+	}
+	slices.Sort(state.Player.Flags)
+	for _, door := range world.Doors {
+		state.Doors = append(state.Doors, DoorState{Closed: door.IsClosed, Locked: door.Locked})
+	}
+	for item, container := range world.Containers {
+		state.Containers[item] = saveStorage(container)
+	}
+	return state
+}
+
+// RestoreState сначала проверяет, что сохранение подходит к миру, и только потом меняет его,
+// так что неподходящее сохранение не оставляет мир в промежуточном состоянии
+func (world *World) RestoreState(player *Player, state *State) error {
+	if err := world.checkState(state); err != nil {
+		return err
+	}
+	for name, roomState := range state.Rooms {
+		room := world.Rooms[name]
+		room.Note = roomState.Note
+		room.LookAroundNote = roomState.LookAroundNote
+		for i, storage := range room.Storages {
+			roomState.Storages[i].restore(storage)
+		}
+		for _, npc := range room.NPCs {
+			if npc.Stats.Health > 0 {
+				npc.HP = roomState.NPCs[npc.Name]
+			}
+		}
+		for i, hook := range room.Hooks {
+			hook.fired = slices.Contains(roomState.FiredHooks, i)
+		}
+	}
+	for i, door := range world.Doors {
+		door.IsClosed = state.Doors[i].Closed
+		door.Locked = state.Doors[i].Locked
+	}
+	for item, containerState := range state.Containers {
+		containerState.restore(world.Containers[item])
+	}
+	world.Clock.Minute = state.Minute
+	world.rewind(state.Draws)
+	player.CurrentRoom = world.Rooms[state.Player.Room]
+	player.Inventory = slices.Clone(state.Player.Inventory)
+	player.Equipment = make(map[string]Item, len(state.Player.Worn))
+	for _, item := range state.Player.Worn {
+		player.Equipment[world.slot(item)] = item
+	}
+	player.Quests = maps.Clone(state.Player.Quests)
+	if player.Quests == nil {
+		player.Quests = make(map[string]QuestProgress)
+	}
+	player.Visited = make(map[string]bool, len(state.Player.Visited))
+	for _, name := range state.Player.Visited {
+		player.Visited[name] = true
+	}
+	player.Flags = make(map[string]bool, len(state.Player.Flags))
+	for _, flag := range state.Player.Flags {
+		player.Flags[flag] = true
+	}
+	player.Dialogue = nil
+	player.Health = state.Player.Health
+	return nil
+}
+
+func (world *World) checkState(state *State) error {
+	if state.Version != StateVersion {
+		return fmt.Errorf("unsupported save version %d, expected %d", state.Version, StateVersion)
+	}
+	if len(state.Rooms) != len(world.Rooms) {
+		return fmt.Errorf("save has %d rooms, world has %d", len(state.Rooms), len(world.Rooms))
+	}
+	for name, roomState := range state.Rooms {
+		room, ok := world.Rooms[name]
+		if !ok {
+			return fmt.Errorf("unknown room %q", name)
+		}
+		if len(roomState.Storages) != len(room.Storages) {
+			return fmt.Errorf("room %q: save has %d storages, world has %d", name, len(roomState.Storages), len(room.Storages))
+		}
+		for _, npc := range room.NPCs {
+			if _, ok := roomState.NPCs[npc.Name]; npc.Stats.Health > 0 && !ok {
+				return fmt.Errorf("room %q: no health of npc %q", name, npc.Name)
+			}
+		}
+		for _, i := range roomState.FiredHooks {
+			if i < 0 || i >= len(room.Hooks) {
+				return fmt.Errorf("room %q: bad hook %d", name, i)
+			}
+		}
+	}
+	if len(state.Doors) != len(world.Doors) {
+		return fmt.Errorf("save has %d doors, world has %d", len(state.Doors), len(world.Doors))
+	}
+	if len(state.Containers) != len(world.Containers) {
+		return fmt.Errorf("save has %d containers, world has %d", len(state.Containers), len(world.Containers))
+	}
+	for item := range state.Containers {
+		if _, ok := world.Containers[item]; !ok {
+			return fmt.Errorf("unknown container %q", item)
+		}
+	}
+	for name, progress := range state.Player.Quests {
+		quest := world.quest(name)
+		if quest == nil {
+			return fmt.Errorf("unknown quest %q", name)
+		}
+		if progress.Step < 0 || progress.Step > len(quest.Steps) {
+			return fmt.Errorf("quest %q: bad step %d", name, progress.Step)
+		}
+	}
+	slots := make(map[string]bool, len(state.Player.Worn))
+	for _, item := range state.Player.Worn {
+		slot := world.slot(item)
+		if slot == "" || slots[slot] {
+			return fmt.Errorf("cant wear %q", item)
+		}
+		slots[slot] = true
+	}
+	if state.Player.Health <= 0 || state.Player.Health > world.PlayerStats.Health {
+		return fmt.Errorf("bad player health %d", state.Player.Health)
+	}
+	if state.Minute < 0 {
+		return fmt.Errorf("bad minute %d", state.Minute)
+	}
+	if state.Draws < 0 {
+		return fmt.Errorf("bad draws %d", state.Draws)
+	}
+	if _, ok := world.Rooms[state.Player.Room]; !ok {
+		return fmt.Errorf("unknown player room %q", state.Player.Room)
+	}
+	return nil
+}
+
+func WriteState(w io.Writer, state *State) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(state)
+}
+
+func ReadState(r io.Reader) (*State, error) {
+	state := &State{}
+	if err := json.NewDecoder(r).Decode(state); err != nil {
+		return nil, fmt.Errorf("cant decode save: %s", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,98 @@
+package world
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Validate ищет в мире ошибки, которые иначе находятся только игрой: недостижимые комнаты,
+// проходы в никуда, несогласованные двери и задания, которые нельзя выполнить.
+// Загрузчик уже отсекает часть из них, но мир можно собрать и без файла
+func (world *World) Validate() []error {
+	var problems []error
+	names := make([]string, 0, len(world.Rooms))
+	for name := range world.Rooms {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, exit := range world.Rooms[name].Exits {
+			switch {
+			case exit.To == nil:
+				problems = append(problems, fmt.Errorf("room %q, exit %q leads nowhere", name, exit.Name))
+			case world.Rooms[exit.To.Name] != exit.To:
+				problems = append(problems, fmt.Errorf("room %q, exit %q leads to room %q outside the world", name, exit.Name, exit.To.Name))
+			case exit.Door != nil && !slices.Contains(world.Doors, exit.Door):
+				problems = append(problems, fmt.Errorf("room %q, exit %q uses door %q outside the world", name, exit.Name, exit.Door.ID))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		// остальные проверки ходят по проходам и на битых проходах дадут лишние ошибки
+		return problems
+	}
+	if err := world.checkExits(); err != nil {
+		problems = append(problems, err)
+	}
+
+	reached, items := world.explore(false)
+	for _, name := range names {
+		if !reached[world.Rooms[name]] {
+			problems = append(problems, fmt.Errorf("room %q is unreachable from %q", name, world.Start.Name))
+		}
+	}
+	// предметы могут появиться не только в хранилищах
+	for _, quest := range world.Quests {
+		world.collect(quest.Reward.Items, items)
+	}
+	for _, interaction := range world.Interactions {
+		world.collect(interaction.Spawn, items)
+	}
+	flags := make(map[string]bool)
+	for _, room := range world.Rooms {
+		for _, npc := range room.NPCs {
+			world.collect(npc.Loot, items)
+			for _, line := range npc.Lines {
+				for _, choice := range line.Choices {
+					world.collect(choice.Give, items)
+					flags[choice.SetFlag] = true
+				}
+			}
+		}
+	}
+	for _, quest := range world.Quests {
+		for _, step := range quest.Steps {
+			if err := world.checkReachable(step.Done, reached, items, flags); err != nil {
+				problems = append(problems, fmt.Errorf("quest %q, step %q: %s", quest.Name, step.Text, err))
+			}
+		}
+	}
+	return problems
+}
+
+// checkReachable проверяет, что условие можно выполнить в достижимой части мира
+func (world *World) checkReachable(cond Condition, reached map[*Room]bool, items map[Item]bool, flags map[string]bool) error {
+	for _, item := range cond.Wears {
+		if world.slot(item) == "" {
+			return fmt.Errorf("item %q can't be worn", item)
+		}
+	}
+	for _, item := range append(slices.Clone(cond.HasItems), cond.Wears...) {
+		if !items[item] {
+			return fmt.Errorf("item %q can't be found", item)
+		}
+	}
+	for _, name := range []string{cond.InRoom, cond.Visited} {
+		if name != "" && !reached[world.Rooms[name]] {
+			return fmt.Errorf("room %q is unreachable", name)
+		}
+	}
+	if cond.Flag != "" && !flags[cond.Flag] {
+		return fmt.Errorf("flag %q is never set", cond.Flag)
+	}
+	if door := world.Door(cond.DoorOpen); door != nil && door.Locked && !items[door.Key] {
+		return fmt.Errorf("key %q of door %q can't be found", door.Key, door.ID)
+	}
+	return nil
+}
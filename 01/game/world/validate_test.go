@@ -0,0 +1,65 @@
+package world
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	world, err := Load(strings.NewReader(exitsWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := world.Validate(); len(problems) > 0 {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	world, err = Load(strings.NewReader(`{
+		"start": "холл",
+		"rooms": [
+			{"name": "холл", "exits": [{"to": "двор"}]},
+			{"name": "двор", "exits": [{"to": "холл"}]},
+			{"name": "чердак", "exits": [{"to": "холл", "one_way": true}]}
+		],
+		"quests": [{"name": "ремонт", "room": "холл", "steps": [
+			{"text": "найти лестницу", "done": {"has_items": ["лестница"]}},
+			{"text": "залезть на чердак", "done": {"visited": "чердак"}}
+		]}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, problem := range world.Validate() {
+		got = append(got, problem.Error())
+	}
+	expected := []string{
+		`room "чердак" is unreachable from "холл"`,
+		`quest "ремонт", step "найти лестницу": item "лестница" can't be found`,
+		`quest "ремонт", step "залезть на чердак": room "чердак" is unreachable`,
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got problems:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestMap(t *testing.T) {
+	world, err := Load(strings.NewReader(exitsWorld))
+	if err != nil {
+		t.Fatal(err)
+	}
+	player := world.NewPlayer()
+	player.WearItem("рюкзак")
+	player.TakeItem("ключ")
+	player.OpenStorage("дверца")
+	player.Go("кладовка")
+	expected := "холл\n" +
+		"├─ кладовка * (дверца)\n" +
+		"└─ ?"
+	if got := player.Map(); got != expected {
+		t.Errorf("got map:\n%s\nexpected:\n%s", got, expected)
+	}
+	if dot := world.DOT(); !strings.Contains(dot, `"двор" -> "подвал";`) {
+		t.Errorf("one-way exit missing in:\n%s", dot)
+	}
+}
@@ -0,0 +1,116 @@
+package world
+
+// Look - описание комнаты данными, а не склеенным текстом. Текст из него собирает
+// Renderer игрока, так что клиентам не нужно разбирать русские ответы.
+// Тексты уже переведены на язык игрока
+type Look struct {
+	Room        string `json:"room"`
+	Description string `json:"description"`
+	// закрытые хранилища и открытые, в которых что-то есть
+	Storages []StorageView `json:"storages"`
+	NPCs     []string      `json:"npcs"`
+	Tasks    []string      `json:"tasks"`
+	Exits    []ExitView    `json:"exits"`
+	// реплики обработчиков комнаты, сработавших при осмотре
+	Notes []string `json:"notes,omitempty"`
+}
+
+// View - всё, что видит игрок: комната, инвентарь и состояние
+type View struct {
+	Look
+	// описание, которое игрок видит, входя в комнату
+	Note      string     `json:"note"`
+	Inventory []ItemView `json:"inventory"`
+	Worn      []string   `json:"worn"`
+	Health    int        `json:"health"`
+	Time      string     `json:"time"`
+}
+
+// StorageView - хранилище комнаты. Содержимое закрытого хранилища не показывается
+type StorageView struct {
+	Name   string     `json:"name,omitempty"`
+	Place  string     `json:"place"`
+	Closed bool       `json:"closed,omitempty"`
+	Items  []ItemView `json:"items"`
+}
+
+// ItemView - предмет, у открытого предмета-контейнера вместе с содержимым
+type ItemView struct {
+	Name  string     `json:"name"`
+	Items []ItemView `json:"items,omitempty"`
+}
+
+type ExitView struct {
+	Name   string `json:"name"`
+	Door   string `json:"door,omitempty"`
+	Closed bool   `json:"closed,omitempty"`
+}
+
+func (player *Player) Look() Look {
+	room := player.CurrentRoom
+	look := Look{
+		Room:        player.T(room.Name),
+		Description: player.T(room.LookAroundNote),
+		Storages:    make([]StorageView, 0, len(room.Storages)),
+		NPCs:        translateAll(player, player.NPCNames()),
+		Tasks:       translateAll(player, player.pendingTasks(room)),
+		Exits:       make([]ExitView, 0, len(room.Exits)),
+	}
+	for _, storage := range room.Storages {
+		if !storage.Closed && len(storage.Items) == 0 {
+			continue
+		}
+		look.Storages = append(look.Storages, StorageView{
+			Name:   player.T(storage.Name),
+			Place:  player.T(storage.NameInCase),
+			Closed: storage.Closed,
+			Items:  player.itemViews(storage),
+		})
+	}
+	for _, exit := range room.Exits {
+		exitView := ExitView{Name: player.T(exit.Name)}
+		if exit.Door != nil {
+			exitView.Door = player.T(exit.Door.Name)
+			exitView.Closed = exit.Door.IsClosed
+		}
+		look.Exits = append(look.Exits, exitView)
+	}
+	return look
+}
+
+func (player *Player) View() View {
+	return View{
+		Look:      player.Look(),
+		Note:      player.T(player.CurrentRoom.Note),
+		Inventory: player.itemViews(&Storage{Items: player.Inventory}),
+		Worn:      translateAll(player, player.Worn()),
+		Health:    player.Health,
+		Time:      player.ShowTime(),
+	}
+}
+
+// itemViews - видимое содержимое хранилища, у закрытого пустой список
+func (player *Player) itemViews(storage *Storage) []ItemView {
+	views := make([]ItemView, 0, len(storage.Items))
+	if storage.Closed {
+		return views
+	}
+	for _, item := range storage.Items {
+		view := ItemView{Name: player.T(string(item))}
+		if container, ok := player.World.Containers[item]; ok && len(container.Items) > 0 {
+			view.Items = player.itemViews(container)
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// translateAll переводит названия, пустой список остаётся пустым, а не nil,
+// чтобы в JSON был [], а не null
+func translateAll[T ~string](player *Player, names []T) []string {
+	translated := make([]string, 0, len(names))
+	for _, name := range names {
+		translated = append(translated, player.translate(string(name)))
+	}
+	return translated
+}
@@ -0,0 +1,51 @@
+package world
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// World - игровой мир: все комнаты по названиям и комната, в которой появляются игроки.
+// Мир может быть общим для нескольких игроков, изменять его можно только под блокировкой
+type World struct {
+	sync.Mutex
+	Rooms  map[string]*Room
+	Doors  []*Door
+	Start  *Room
+	Events Bus
+	Items  map[Item]*ItemType
+	// содержимое предметов-контейнеров. Контейнер существует в мире в одном экземпляре
+	// (это проверяет загрузчик), поэтому его название и есть экземпляр
+	Containers map[Item]*Storage
+	// применения предметов, подходящим считается первое по порядку
+	Interactions []*Interaction
+	Quests       []*Quest
+	Clock        Clock
+	// начальные характеристики игроков
+	PlayerStats Stats
+	Combat      CombatRules
+	rand        *rand.Rand
+	source      *countedSource
+	// переводы текстов мира: названий, описаний, реплик
+	Translations map[Locale]map[string]string
+	// мир общий для подключённых к серверу игроков. Сохранение описывает мир и одного игрока,
+	// поэтому загружать его в общий мир нельзя: у остальных игроков задвоились бы предметы
+	Shared bool
+	// подкаталог сохранений: у каждой сессии HTTP API свои слоты, пусто - общий каталог
+	SaveScope string
+	// растёт при каждом изменении мира командой игрока, см. History
+	revision int
+}
+
+func (world *World) NewPlayer() *Player {
+	return &Player{
+		World:       world,
+		CurrentRoom: world.Start,
+		Inventory:   make([]Item, 0, 5),
+		Equipment:   make(map[string]Item),
+		Quests:      make(map[string]QuestProgress, len(world.Quests)),
+		Visited:     map[string]bool{world.Start.Name: true},
+		Flags:       make(map[string]bool),
+		Health:      world.PlayerStats.Health,
+	}
+}